@@ -0,0 +1,226 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strings"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidRuleField matches the RuleError of Field, cross field or condition field
+	// that can't be parsed or found, like errors.Is(err, ErrInvalidRuleField).
+	ErrInvalidRuleField = errors.New("invalid rule field")
+	// ErrInvalidTag matches the RuleError of the tag that isn't registered or has invalid param,
+	// or the param of inclusion tag isn't registered, or the normalizer isn't registered.
+	ErrInvalidTag = errors.New("invalid tag")
+	// ErrInvalidTarget matches the error of invalid data or toStruct,
+	// like data is not a struct, or toStruct is not a pointer.
+	ErrInvalidTarget = errors.New("invalid target")
+)
+
+// RuleError is a problem of the rule at Index of the rules.
+type RuleError struct {
+	Index int
+	Field string
+	Tag   string
+	Err   error
+
+	// Rule is the offending rule.
+	Rule Rule
+	// Kind is ErrInvalidRuleField, ErrInvalidTag or ErrValidatorPanic, errors.Is(re, Kind) is true.
+	// It is nil for the unexpected panic of this package.
+	Kind error
+	// Stack is the stack trace where the problem is found, or where the panic happened.
+	Stack []byte
+}
+
+func (re *RuleError) Error() string {
+	return fmt.Sprintf("rule %v (Field: %v, Tag: %v): %v", re.Index, re.Field, re.Tag, re.Err)
+}
+
+func (re *RuleError) Cause() error {
+	return re.Err
+}
+
+func (re *RuleError) Unwrap() error {
+	return re.Err
+}
+
+// Is reports whether target is Kind of re.
+func (re *RuleError) Is(target error) bool {
+	return re.Kind != nil && target == re.Kind
+}
+
+// ruleError fills Index, Field and Rule of err if it is *RuleError,
+// and Tag and Stack if they are empty, the other errors are returned as it is.
+func ruleError(err error, index int, rule Rule) error {
+	re, ok := err.(*RuleError)
+	if !ok {
+		return err
+	}
+
+	re.Index = index
+	re.Field = rule.Field
+	re.Rule = rule
+	if re.Tag == "" {
+		re.Tag = rule.Tag
+	}
+	if re.Stack == nil {
+		re.Stack = debug.Stack()
+	}
+
+	return re
+}
+
+// invalidTargetError is an error of ErrInvalidTarget with the detail message.
+type invalidTargetError string
+
+func (e invalidTargetError) Error() string {
+	return string(e)
+}
+
+// Is reports whether target is ErrInvalidTarget.
+func (e invalidTargetError) Is(target error) bool {
+	return target == ErrInvalidTarget
+}
+
+// RuleErrors contains all problems of the rules.
+type RuleErrors []*RuleError
+
+func (res RuleErrors) Error() string {
+	errStrs := []string{}
+	for _, re := range res {
+		errStrs = append(errStrs, re.Error())
+	}
+
+	return "invalid rules: " + strings.Join(errStrs, "; ")
+}
+
+// CheckRules checks rules against the struct type of sample without any data,
+// so you can find invalid rules in init() or tests, before they are used in DoRules.
+//
+// sample can be a struct, a pointer to struct, or their reflect.Type.
+//
+// It returns RuleErrors that contains every problem of the rules:
+// * Rule.Field, cross field or condition field not found in the struct
+// * tag not registered or param of tag invalid
+// * param of inclusion tag not registered by RegisterInclusionValidationParam
+//
+// Fields through interface{} can't be checked, they are resolved in DoRules.
+func (v *Validate) CheckRules(sample interface{}, rules []Rule) error {
+	_, err := v.Compile(sample, rules, "")
+	return err
+}
+
+// MustRules is same as CheckRules, but panics if rules are invalid,
+// and returns the rules, so you can use it to define rules:
+//
+//	var userRules = validate.MustRules(User{}, []validator.Rule{...})
+func (v *Validate) MustRules(sample interface{}, rules []Rule) []Rule {
+	if err := v.CheckRules(sample, rules); err != nil {
+		panic(err)
+	}
+
+	return rules
+}
+
+func (v *Validate) checkCompiledRule(typ reflect.Type, index int, cr *compiledRule) RuleErrors {
+	rerrs := RuleErrors{}
+
+	if cr.rule.Func == nil {
+		fieldType, err := typeOfPath(typ, cr.path)
+		if err != nil {
+			rerrs = append(rerrs, &RuleError{Tag: cr.rule.Tag, Kind: ErrInvalidRuleField, Err: err})
+		} else {
+			rerrs = append(rerrs, v.checkTags(fieldType, cr.varTag)...)
+		}
+	}
+
+	for _, crossTag := range cr.crossTags {
+		if _, err := typeOfPath(typ, crossTag.path); err != nil {
+			rerrs = append(rerrs, &RuleError{Tag: crossTag.tag + tagKeySeparator + crossTag.path.path, Kind: ErrInvalidRuleField, Err: err})
+		}
+	}
+
+	for _, cond := range cr.conds {
+		condType, err := typeOfPath(typ, cond.path)
+		if err != nil {
+			rerrs = append(rerrs, &RuleError{Tag: cond.Tag, Kind: ErrInvalidRuleField, Err: err})
+			continue
+		}
+		rerrs = append(rerrs, v.checkTags(condType, cond.Tag)...)
+	}
+
+	for _, rerr := range rerrs {
+		ruleError(rerr, index, cr.rule)
+	}
+
+	return rerrs
+}
+
+// checkTags checks every tag of tags, it returns a RuleError with Tag and Err for each invalid tag.
+func (v *Validate) checkTags(typ reflect.Type, tags string) RuleErrors {
+	if tags == "" {
+		return nil
+	}
+
+	// dive applies the following tags to elements,
+	// so they can't be checked one by one.
+	if strings.Contains(tags, "dive") {
+		if err := v.checkTag(typ, tags); err != nil {
+			return RuleErrors{{Tag: tags, Kind: ErrInvalidTag, Err: err}}
+		}
+		return nil
+	}
+
+	rerrs := RuleErrors{}
+	for _, tag := range splitTag(tags) {
+		if err := v.checkTag(typ, tag); err != nil {
+			if re, ok := err.(*RuleError); ok {
+				re.Tag = tag
+				rerrs = append(rerrs, re)
+			} else {
+				rerrs = append(rerrs, &RuleError{Tag: tag, Kind: ErrInvalidTag, Err: err})
+			}
+			continue
+		}
+
+		if getTagBefore(tag) == "inclusion" {
+			if _, ok := v.inclusionValidations[getTagAfter(tag)]; !ok {
+				rerrs = append(rerrs, &RuleError{Tag: tag, Kind: ErrInvalidTag, Err: errors.Errorf("inclusion param %v is not registered", getTagAfter(tag))})
+			}
+		}
+	}
+
+	return rerrs
+}
+
+// checkTag validates the zero value of typ with tag,
+// the unregistered tags and invalid params will panic in go-playground/validator.
+// The panic of the registered validation is returned as RuleError of ErrValidatorPanic.
+func (v *Validate) checkTag(typ reflect.Type, tag string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(validatorPanic); ok {
+				err = panicRuleError(r)
+				return
+			}
+			err = errors.Errorf("tag %v invalid: %v", tag, r)
+		}
+	}()
+
+	var zero interface{}
+	if typ != nil {
+		zero = reflect.Zero(typ).Interface()
+	}
+
+	if _, ok := v.GPValidate.Var(zero, tag).(*validator.InvalidValidationError); ok {
+		return errors.Errorf("tag %v invalid", tag)
+	}
+
+	return nil
+}
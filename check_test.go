@@ -0,0 +1,199 @@
+package validator_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	gpvalidator "github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+func TestValidate_CheckRules(t *testing.T) {
+	validate := validator.New()
+
+	fatalassert.NoError(t, validate.RegisterInclusionValidationParam("gender", []string{"U", "M", "F"}))
+
+	fatalassert.NoError(t, validate.CheckRules(info{}, infoRules))
+	fatalassert.NoError(t, validate.CheckRules(&user{}, userRules))
+	fatalassert.NoError(t, validate.CheckRules(info{}, []validator.Rule{{Field: "Name", Tag: "inclusion=gender"}}))
+
+	err := validate.CheckRules(info{}, []validator.Rule{
+		{Field: "Name", Tag: "required"},
+		{Field: "NotExist", Tag: "required"},
+		{Field: "Name", Tag: "required,unknown_tag,lte=20,inclusion=color"},
+		{Field: "Password", Tag: "eqfield=NotExist"},
+		{Field: "Age", Tag: "min=20", If: []validator.Condition{{Field: "Name", Tag: "unknown_tag"}}},
+	})
+
+	var rerrs validator.RuleErrors
+	if !errors.As(err, &rerrs) {
+		t.Fatalf("should return RuleErrors, but got %v", err)
+	}
+
+	got := []string{}
+	for _, rerr := range rerrs {
+		got = append(got, fmt.Sprintf("%v %v %v %v %v", rerr.Index, rerr.Field, rerr.Tag, rerr.Kind, rerr.Rule.Tag))
+	}
+
+	want := []string{
+		"1 NotExist required invalid rule field required",
+		"2 Name unknown_tag invalid tag required,unknown_tag,lte=20,inclusion=color",
+		"2 Name inclusion=color invalid tag required,unknown_tag,lte=20,inclusion=color",
+		"3 Password eqfield=NotExist invalid rule field eqfield=NotExist",
+		"4 Age unknown_tag invalid tag min=20",
+	}
+
+	fatalassert.Equal(t, want, got)
+}
+
+func TestValidate_MustRules(t *testing.T) {
+	validate := validator.New()
+
+	rules := validate.MustRules(info{}, infoRules)
+	if len(rules) != len(infoRules) {
+		t.Fatal("should return the rules")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("should panic")
+		}
+	}()
+
+	validate.MustRules(info{}, []validator.Rule{{Field: "NotExist", Tag: "required"}})
+}
+
+func TestValidate_TryDoRules(t *testing.T) {
+	validate := validator.New()
+
+	cases := []struct {
+		err       error
+		wantKind  error
+		wantField string
+	}{
+		{
+			err:       validate.TryDoRulesToStruct(info{}, []validator.Rule{{Field: "NotExist", Tag: "required"}}, &infoStringsError{}),
+			wantKind:  validator.ErrInvalidRuleField,
+			wantField: "NotExist",
+		},
+		{
+			err:       validate.TryDoRulesToStructAndSetNil(info{}, []validator.Rule{{Field: "Name", Tag: "required"}, {Field: "Age", Tag: "unknown_tag"}}, &infoStringsError{}),
+			wantKind:  validator.ErrInvalidTag,
+			wantField: "Age",
+		},
+		{
+			err:      validate.TryDoRulesToStruct(info{}, infoRules, infoStringsError{}),
+			wantKind: validator.ErrInvalidTarget,
+		},
+		{
+			err:      validate.TryDoRulesToStruct("not struct", infoRules, &infoStringsError{}),
+			wantKind: validator.ErrInvalidTarget,
+		},
+	}
+
+	for i, c := range cases {
+		if !errors.Is(c.err, c.wantKind) {
+			t.Fatalf("case %v: %v should match %v", i, c.err, c.wantKind)
+		}
+
+		var rerr *validator.RuleError
+		if errors.As(c.err, &rerr) {
+			fatalassert.Equal(t, c.wantField, rerr.Rule.Field)
+		} else if c.wantField != "" {
+			t.Fatalf("case %v: %v should be RuleError", i, c.err)
+		}
+	}
+
+	_, err := validate.TryDoRulesToProtoError(info{}, []validator.Rule{{Field: "Name", Tag: "required", If: []validator.Condition{{Field: "Age", Tag: "unknown_tag"}}}})
+	if !errors.Is(err, validator.ErrInvalidTag) {
+		t.Fatalf("%v should match ErrInvalidTag", err)
+	}
+
+	protoErr, err := validate.TryDoRulesToProtoError(user{}, userRules)
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, 3, len(protoErr.FieldViolations))
+}
+
+func TestValidate_DoRulesWithPanic(t *testing.T) {
+	validate := validator.New()
+	fatalassert.NoError(t, validate.RegisterValidation("broken", func(fl gpvalidator.FieldLevel) bool {
+		panic("broken validation")
+	}))
+	fatalassert.NoError(t, validate.RegisterStructValidation(info{}, func(data interface{}) validator.Errors {
+		panic(errors.New("broken struct validation"))
+	}))
+	fatalassert.NoError(t, validate.RegisterContextValidation("broken_context", func(ctx context.Context, value interface{}, param string) (bool, error) {
+		panic("broken context validation")
+	}))
+
+	cases := []struct {
+		rules     []validator.Rule
+		wantKind  error
+		wantIndex int
+		wantErr   string
+	}{
+		{
+			rules:     []validator.Rule{{Field: "Name", Tag: "required"}, {Field: "Name", Tag: "broken"}},
+			wantKind:  validator.ErrValidatorPanic,
+			wantIndex: 1,
+			wantErr:   "rule 1 (Field: Name, Tag: broken): validation broken panicked: broken validation",
+		},
+		{
+			rules:     []validator.Rule{{Field: "Name", Tag: "required", When: func(data interface{}) bool { panic("broken when") }}},
+			wantKind:  validator.ErrValidatorPanic,
+			wantIndex: 0,
+			wantErr:   "rule 0 (Field: Name, Tag: required): When panicked: broken when",
+		},
+		{
+			rules:     []validator.Rule{{Field: "Name", Tag: "required,broken_context"}},
+			wantKind:  validator.ErrValidatorPanic,
+			wantIndex: 0,
+			wantErr:   "rule 0 (Field: Name, Tag: required,broken_context): context validation broken_context panicked: broken context validation",
+		},
+		{
+			rules:     []validator.Rule{{Field: "Name", Tag: "unknown_tag"}},
+			wantKind:  validator.ErrInvalidTag,
+			wantIndex: 0,
+			wantErr:   "rule 0 (Field: Name, Tag: unknown_tag): Undefined validation function 'unknown_tag' on field ''",
+		},
+		{
+			rules:     nil,
+			wantKind:  validator.ErrValidatorPanic,
+			wantIndex: -1,
+			wantErr:   "rule -1 (Field: , Tag: ): struct validation of validator_test.info panicked: broken struct validation",
+		},
+	}
+
+	for _, c := range cases {
+		verrs, err := validate.DoRules(info{Name: "name"}, c.rules)
+		if verrs != nil {
+			t.Fatalf("should not return Errors, but got %v", verrs)
+		}
+
+		var rerr *validator.RuleError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("should return RuleError, but got %v", err)
+		}
+		if !errors.Is(err, c.wantKind) {
+			t.Fatalf("%v should match %v", err, c.wantKind)
+		}
+		fatalassert.Equal(t, c.wantIndex, rerr.Index)
+		fatalassert.Equal(t, c.wantErr, err.Error())
+		if len(rerr.Stack) == 0 {
+			t.Fatal("should have stack")
+		}
+	}
+
+	_, err := validate.DoRules(secretInfo{}, []validator.Rule{{Field: "secret", Tag: "required"}})
+	if !errors.Is(err, validator.ErrInvalidRuleField) || errors.Is(err, validator.ErrInvalidTag) {
+		t.Fatalf("unexported field should be ErrInvalidRuleField, but got %v", err)
+	}
+
+	_, err = validate.DoRules(info{}, []validator.Rule{{Field: "Name", Tag: "required", If: []validator.Condition{{Field: "Age", Tag: "unknown_tag"}}}})
+	if !errors.Is(err, validator.ErrInvalidTag) {
+		t.Fatalf("should be ErrInvalidTag, but got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// compiledRule is a Rule with parsed Field, Tag and conditions.
+type compiledRule struct {
+	rule   Rule
+	path   fieldPath
+	varTag string
+	// presenceTag is the required tags of varTag, only they are checked for the absent value.
+	presenceTag string
+	crossTags   []crossTag
+	// contextTags are the tags registered by RegisterContextValidation.
+	contextTags []contextTag
+	omitEmpty   bool
+	conds       []compiledCondition
+	normalizers []namedNormalizer
+}
+
+type crossTag struct {
+	tag  string
+	path fieldPath
+}
+
+func (v *Validate) compileRule(rule Rule) (compiledRule, error) {
+	cr := compiledRule{rule: rule}
+
+	// Field and Tag are ignored by the struct level rule.
+	if rule.Func == nil {
+		path, err := newFieldPath(rule.Field)
+		if err != nil {
+			return compiledRule{}, &RuleError{Kind: ErrInvalidRuleField, Err: err}
+		}
+		cr.path = path
+
+		varTags := []string{}
+		presenceTags := []string{}
+		for _, tag := range splitTag(rule.Tag) {
+			tagBefore := getTagBefore(tag)
+			if isCrossField(tagBefore) {
+				otherPath, err := newFieldPath(getTagAfter(tag))
+				if err != nil {
+					return compiledRule{}, &RuleError{Tag: tag, Kind: ErrInvalidRuleField, Err: err}
+				}
+				cr.crossTags = append(cr.crossTags, crossTag{tag: tagBefore, path: otherPath})
+			} else if fn, ok := v.contextFuncs[tagBefore]; ok {
+				param := ""
+				if strings.Contains(tag, tagKeySeparator) {
+					param = getTagAfter(tag)
+				}
+				cr.contextTags = append(cr.contextTags, contextTag{tag: tagBefore, param: param, fn: fn})
+			} else {
+				if tag == "omitempty" {
+					cr.omitEmpty = true
+				}
+				if isPresenceTag(tagBefore) {
+					presenceTags = append(presenceTags, tag)
+				}
+				varTags = append(varTags, tag)
+			}
+		}
+		cr.varTag = strings.Join(varTags, tagSeparator)
+		cr.presenceTag = strings.Join(presenceTags, tagSeparator)
+
+		normalizers, err := v.compileNormalizers(rule.Normalize)
+		if err != nil {
+			return compiledRule{}, err
+		}
+		cr.normalizers = normalizers
+	}
+
+	for _, cond := range rule.If {
+		condPath, err := newFieldPath(cond.Field)
+		if err != nil {
+			return compiledRule{}, &RuleError{Tag: cond.Tag, Kind: ErrInvalidRuleField, Err: err}
+		}
+		cr.conds = append(cr.conds, compiledCondition{Condition: cond, path: condPath})
+	}
+
+	return cr, nil
+}
+
+// isPresenceTag reports whether tag checks the presence of the value, like required and required_with.
+func isPresenceTag(tag string) bool {
+	return strings.HasPrefix(tag, "required") || tag == "strict_required"
+}
+
+// Plan is the rules compiled for a struct type by Compile.
+// It can be reused and is safe for concurrent use.
+type Plan struct {
+	validate *Validate
+	typ      reflect.Type
+	tagName  string
+	rules    []compiledRule
+}
+
+// Compile checks rules against the struct type of sample once and returns a reusable Plan,
+// Plan.Run is same as DoRulesWithTagName, but doesn't parse the rules again.
+//
+// sample can be a struct, a pointer to struct, or their reflect.Type.
+//
+// It returns RuleErrors if any rule is invalid, see CheckRules.
+func (v *Validate) Compile(sample interface{}, rules []Rule, tagName string) (*Plan, error) {
+	typ, ok := sample.(reflect.Type)
+	if !ok {
+		typ = reflect.TypeOf(sample)
+	}
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, invalidTargetError("sample should be a struct or a pointer to struct")
+	}
+
+	plan := &Plan{validate: v, typ: typ, tagName: tagName}
+	rerrs := RuleErrors{}
+	for i, rule := range rules {
+		cr, err := v.compileRule(rule)
+		if err != nil {
+			rerrs = append(rerrs, ruleError(err, i, rule).(*RuleError))
+			continue
+		}
+		rerrs = append(rerrs, v.checkCompiledRule(typ, i, &cr)...)
+		plan.rules = append(plan.rules, cr)
+	}
+
+	if len(rerrs) > 0 {
+		return nil, rerrs
+	}
+
+	return plan, nil
+}
+
+// Run validates data with the compiled rules,
+// data must be the same struct type or a pointer to it.
+func (p *Plan) Run(data interface{}) (Errors, error) {
+	return p.RunContext(context.Background(), data)
+}
+
+// RunContext is same as Run, ctx is passed to the context validations.
+func (p *Plan) RunContext(ctx context.Context, data interface{}) (Errors, error) {
+	val, err := dataValue(data)
+	if err != nil {
+		return nil, err
+	}
+	if val.Type() != p.typ {
+		return nil, invalidTargetError(fmt.Sprintf("data should be %v, but got %v", p.typ, val.Type()))
+	}
+
+	return p.validate.runRules(ctx, data, val, p.rules, p.tagName)
+}
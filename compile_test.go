@@ -0,0 +1,103 @@
+package validator_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+func TestValidate_Compile(t *testing.T) {
+	validate := validator.New()
+
+	plan, err := validate.Compile(reflect.TypeOf(info{}), infoRules, "")
+	fatalassert.NoError(t, err)
+
+	for _, data := range []interface{}{info{}, &info{}, info{Name: "name", Age: 200, ZipCode: "123-1234"}} {
+		wantVerrs, err := validate.DoRules(data, infoRules)
+		fatalassert.NoError(t, err)
+
+		gotVerrs, err := plan.Run(data)
+		fatalassert.NoError(t, err)
+
+		fatalassert.Equal(t, wantVerrs, gotVerrs)
+	}
+
+	if _, err := plan.Run(user{}); err == nil {
+		t.Fatal("should return error when data type is different")
+	}
+}
+
+func TestValidate_CompileWithTagNameAndElementPaths(t *testing.T) {
+	validate := validator.New()
+
+	plan, err := validate.Compile(&order{}, []validator.Rule{{Field: "Items[*].SKU", Tag: "required"}}, "json")
+	fatalassert.NoError(t, err)
+
+	verrs, err := plan.Run(&order{Items: []orderItem{{SKU: "a"}, {}}})
+	fatalassert.NoError(t, err)
+
+	fatalassert.Equal(t, validator.Errors{{Field: "items[1].sku", Tag: "required", Value: ""}}, verrs)
+}
+
+type secretInfo struct {
+	info
+	secret string
+}
+
+func TestValidate_CompileWithInvalidRules(t *testing.T) {
+	validate := validator.New()
+
+	rulesList := [][]validator.Rule{
+		{{Field: "NotExist", Tag: "required"}},
+		{{Field: "secret", Tag: "required"}},
+		{{Field: "Name[0]", Tag: "required"}},
+		{{Field: "Name", Tag: "unknown_tag"}},
+		{{Field: "Age", Tag: "min=abc"}},
+		{{Field: "Name", Tag: "eqfield=NotExist"}},
+		{{Field: "Name", Tag: "required", If: []validator.Condition{{Field: "NotExist", Tag: "required"}}}},
+		{{Field: "Name", Tag: "required", If: []validator.Condition{{Field: "Age", Tag: "unknown_tag"}}}},
+	}
+
+	for _, rules := range rulesList {
+		if _, err := validate.Compile(secretInfo{}, rules, ""); err == nil {
+			t.Fatalf("%v should return error", rules[0])
+		}
+	}
+
+	_, err := validate.DoRules(secretInfo{}, []validator.Rule{{Field: "secret", Tag: "required"}})
+	if !errors.Is(err, validator.ErrInvalidRuleField) {
+		t.Fatalf("should return ErrInvalidRuleField, but got %v", err)
+	}
+
+	if _, err := validate.Compile("not struct", infoRules, ""); err == nil {
+		t.Fatal("should return error")
+	}
+}
+
+func BenchmarkValidate_DoRules(b *testing.B) {
+	validate := validator.New()
+	data := info{Name: "name", Age: 200}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validate.DoRules(data, infoRules)
+	}
+}
+
+func BenchmarkPlan_Run(b *testing.B) {
+	validate := validator.New()
+	data := info{Name: "name", Age: 200}
+
+	plan, err := validate.Compile(data, infoRules, "")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		plan.Run(data)
+	}
+}
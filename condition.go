@@ -0,0 +1,66 @@
+package validator
+
+import (
+	"reflect"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+)
+
+// Condition is a declarative condition of the Rule.
+//
+// It holds when the value of Field passes Tag.
+// For example, Condition{Field: "Country", Tag: "eq=JP"} holds when Country is "JP",
+// and Condition{Field: "AccountType", Tag: "oneof=business enterprise"} holds
+// when AccountType is "business" or "enterprise".
+//
+// Field uses the same path resolution as Rule.Field,
+// if it resolves to multiple values, all of them must pass Tag.
+// Cross field tags can not be used in Tag.
+type Condition struct {
+	Field string `json:"field" yaml:"field"`
+	Tag   string `json:"tag" yaml:"tag"`
+}
+
+type compiledCondition struct {
+	Condition
+	path fieldPath
+}
+
+// ruleApplies reports whether the When and If of the rule hold for data.
+func (v *Validate) ruleApplies(data interface{}, val reflect.Value, cr *compiledRule) (bool, error) {
+	if cr.rule.When != nil && !callWhen(cr.rule.When, data) {
+		return false, nil
+	}
+
+	for _, cond := range cr.conds {
+		ok, err := v.conditionHolds(val, cond)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (v *Validate) conditionHolds(val reflect.Value, cond compiledCondition) (bool, error) {
+	fields, err := cond.path.resolve(val, "")
+	if err != nil {
+		return false, &RuleError{Tag: cond.Tag, Kind: ErrInvalidRuleField, Err: errors.Wrap(err, "resolve condition failed")}
+	}
+
+	for _, field := range fields {
+		err := v.gpVar(field.interfaceOrNil(), cond.Tag)
+		if _, ok := err.(*validator.InvalidValidationError); ok {
+			return false, &RuleError{Tag: cond.Tag, Kind: ErrInvalidTag, Err: errors.Wrapf(err, "condition %v of %v invalid", cond.Tag, cond.Field)}
+		}
+		if err != nil {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
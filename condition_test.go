@@ -0,0 +1,88 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+type account struct {
+	AccountType string
+	Company     string
+	Country     string
+	Address     struct {
+		Zip string
+	}
+}
+
+var accountRules = []validator.Rule{
+	{Field: "Company", Tag: "required", If: []validator.Condition{{Field: "AccountType", Tag: "eq=business"}}},
+	{Field: "Address.Zip", Tag: "zipcode_jp", When: func(data interface{}) bool {
+		return data.(account).Country == "JP"
+	}},
+}
+
+func TestValidate_DoRulesWithConditions(t *testing.T) {
+	validate := validator.New()
+
+	a := account{AccountType: "personal", Country: "US"}
+	a.Address.Zip = "12345"
+
+	verrs, err := validate.DoRules(a, accountRules)
+	fatalassert.NoError(t, err)
+	if verrs != nil {
+		t.Fatalf("should not return Errors: %v", verrs)
+	}
+
+	a.AccountType = "business"
+	a.Country = "JP"
+
+	verrs, err = validate.DoRules(a, accountRules)
+	fatalassert.NoError(t, err)
+
+	wantVerrs := validator.Errors{
+		{Field: "Company", Tag: "required", Value: ""},
+		{Field: "Address.Zip", Tag: "zipcode_jp", Value: "12345"},
+	}
+
+	fatalassert.Equal(t, wantVerrs, verrs)
+}
+
+func TestValidate_DoRulesWithNestedAndMultipleConditions(t *testing.T) {
+	validate := validator.New()
+
+	rules := []validator.Rule{
+		{Field: "Company", Tag: "required", If: []validator.Condition{
+			{Field: "Address.Zip", Tag: "required"},
+			{Field: "Country", Tag: "oneof=JP CN"},
+		}},
+	}
+
+	a := account{Country: "JP"}
+
+	verrs, err := validate.DoRules(a, rules)
+	fatalassert.NoError(t, err)
+	if verrs != nil {
+		t.Fatalf("should not return Errors: %v", verrs)
+	}
+
+	a.Address.Zip = "123-1234"
+
+	verrs, err = validate.DoRules(a, rules)
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.Errors{{Field: "Company", Tag: "required", Value: ""}}, verrs)
+}
+
+func TestValidate_DoRulesWithInvalidCondition(t *testing.T) {
+	validate := validator.New()
+
+	rules := []validator.Rule{
+		{Field: "Company", Tag: "required", If: []validator.Condition{{Field: "NotExist", Tag: "required"}}},
+	}
+
+	_, err := validate.DoRules(account{}, rules)
+	if err == nil {
+		t.Fatal("should return error")
+	}
+}
@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ContextFunc is a validation that needs a context.Context, like checking the database.
+//
+// value is the value of the field, the pointers are dereferenced, like string for *string,
+// and it is nil if the value is absent or a nil pointer.
+// param is the param of the tag, for example, param is "users.email" for "unique=users.email".
+//
+// It returns false if the validation failed,
+// and returns error only if it can't validate, like the database is down.
+type ContextFunc func(ctx context.Context, value interface{}, param string) (bool, error)
+
+type contextTag struct {
+	tag   string
+	param string
+	fn    ContextFunc
+}
+
+// RegisterContextValidation adds a context validation with the given tag,
+// it can be used in Rule.Tag like other tags, and it gets the ctx of DoRulesContext.
+// DoRules uses context.Background().
+//
+// The context validations of a field run after the other tags of the field,
+// and only when they pass, and they are skipped if the field is empty and has "omitempty" tag.
+//
+// NOTES:
+// - if the key already exists, the previous validation function will be replaced.
+// - this method is not thread-safe it is intended that these all be registered prior to any validation
+func (v *Validate) RegisterContextValidation(tag string, fn ContextFunc) error {
+	if tag == "" {
+		return errors.New("tag can not be empty")
+	}
+	if fn == nil {
+		return errors.New("fn can not be nil")
+	}
+
+	v.contextFuncs[tag] = fn
+
+	return nil
+}
+
+func isEmpty(value interface{}) bool {
+	return value == nil || reflect.ValueOf(value).IsZero()
+}
+
+// UniquenessRepository is used by UniqueValidation to check whether the value is taken.
+type UniquenessRepository interface {
+	// Exists reports whether value exists in the scope,
+	// scope is the param of the tag, like "users.email".
+	Exists(ctx context.Context, scope string, value interface{}) (bool, error)
+}
+
+// UniqueValidation returns a ContextFunc that fails if the value exists in repo,
+// the absent value always passes.
+//
+// For example:
+//
+//	validate.RegisterContextValidation("unique", validator.UniqueValidation(userRepo))
+//	rules := []validator.Rule{{Field: "Email", Tag: "required,simple_email,unique=users.email"}}
+func UniqueValidation(repo UniquenessRepository) ContextFunc {
+	return func(ctx context.Context, value interface{}, param string) (bool, error) {
+		if value == nil {
+			return true, nil
+		}
+
+		exists, err := repo.Exists(ctx, param, value)
+		if err != nil {
+			return false, err
+		}
+
+		return !exists, nil
+	}
+}
+
+// MemoryUniquenessRepository is an in-memory UniquenessRepository for tests.
+type MemoryUniquenessRepository struct {
+	mu     sync.RWMutex
+	values map[string]map[interface{}]bool
+}
+
+func NewMemoryUniquenessRepository() *MemoryUniquenessRepository {
+	return &MemoryUniquenessRepository{values: map[string]map[interface{}]bool{}}
+}
+
+// Add adds values to the scope, values must be comparable.
+func (r *MemoryUniquenessRepository) Add(scope string, values ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.values[scope] == nil {
+		r.values[scope] = map[interface{}]bool{}
+	}
+	for _, value := range values {
+		r.values[scope][value] = true
+	}
+}
+
+func (r *MemoryUniquenessRepository) Exists(ctx context.Context, scope string, value interface{}) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	if !reflect.TypeOf(value).Comparable() {
+		return false, errors.Errorf("value of %v is not comparable", reflect.TypeOf(value))
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.values[scope][value], nil
+}
@@ -0,0 +1,115 @@
+package validator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+type signup struct {
+	Email    string
+	Nickname string
+}
+
+func TestValidate_DoRulesContext(t *testing.T) {
+	validate := validator.New()
+
+	repo := validator.NewMemoryUniquenessRepository()
+	repo.Add("users.email", "taken@example.com")
+	repo.Add("users.nickname", "taken")
+
+	fatalassert.NoError(t, validate.RegisterContextValidation("unique", validator.UniqueValidation(repo)))
+
+	rules := []validator.Rule{
+		{Field: "Email", Tag: "required,simple_email,unique=users.email", Code: "email-invalid"},
+		{Field: "Nickname", Tag: "omitempty,unique=users.nickname"},
+	}
+
+	cases := []struct {
+		data      signup
+		wantVerrs validator.Errors
+	}{
+		{
+			data:      signup{Email: "taken@example.com", Nickname: "taken"},
+			wantVerrs: validator.Errors{{Field: "Email", Tag: "unique", Param: "users.email", Code: "email-invalid", Value: "taken@example.com"}, {Field: "Nickname", Tag: "unique", Param: "users.nickname", Value: "taken"}},
+		},
+		{
+			data:      signup{Email: "invalid"},
+			wantVerrs: validator.Errors{{Field: "Email", Tag: "simple_email", Code: "email-invalid", Value: "invalid"}},
+		},
+		{
+			data: signup{Email: "new@example.com", Nickname: "new"},
+		},
+	}
+
+	for _, c := range cases {
+		verrs, err := validate.DoRulesContext(context.Background(), c.data, rules)
+		fatalassert.NoError(t, err)
+		fatalassert.Equal(t, c.wantVerrs, verrs)
+	}
+
+	// DoRules uses context.Background().
+	verrs, err := validate.DoRules(signup{Email: "taken@example.com"}, rules)
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.Errors{{Field: "Email", Tag: "unique", Param: "users.email", Code: "email-invalid", Value: "taken@example.com"}}, verrs)
+}
+
+type profilePatch struct {
+	Nickname *string
+}
+
+func TestValidate_DoRulesContextWithPointer(t *testing.T) {
+	validate := validator.New()
+
+	repo := validator.NewMemoryUniquenessRepository()
+	repo.Add("users.nickname", "taken")
+
+	fatalassert.NoError(t, validate.RegisterContextValidation("unique", validator.UniqueValidation(repo)))
+
+	rules := []validator.Rule{{Field: "Nickname", Tag: "omitempty,unique=users.nickname"}}
+
+	taken, empty := "taken", ""
+	for _, data := range []profilePatch{{Nickname: &taken}, {Nickname: &empty}, {}} {
+		verrs, err := validate.DoRules(data, rules)
+		fatalassert.NoError(t, err)
+
+		var wantVerrs validator.Errors
+		if data.Nickname == &taken {
+			wantVerrs = validator.Errors{{Field: "Nickname", Tag: "unique", Param: "users.nickname", Value: "taken"}}
+		}
+		fatalassert.Equal(t, wantVerrs, verrs)
+	}
+}
+
+func TestValidate_DoRulesContextWithErrorAndCancel(t *testing.T) {
+	validate := validator.New()
+
+	errDatabase := errors.New("database is down")
+	called := 0
+	fatalassert.NoError(t, validate.RegisterContextValidation("unique", func(ctx context.Context, value interface{}, param string) (bool, error) {
+		called++
+		return false, errDatabase
+	}))
+
+	rules := []validator.Rule{{Field: "Email", Tag: "unique=users.email"}}
+
+	verrs, err := validate.DoRulesContext(context.Background(), signup{Email: "a@b"}, rules)
+	if verrs != nil || errors.Cause(err) != errDatabase {
+		t.Fatalf("should return the database error, but got %v, %v", verrs, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called = 0
+	verrs, err = validate.DoRulesContext(ctx, signup{Email: "a@b"}, rules)
+	if verrs != nil || err != context.Canceled {
+		t.Fatalf("should return context.Canceled, but got %v, %v", verrs, err)
+	}
+	if called != 0 {
+		t.Fatal("should not run the remaining rules")
+	}
+}
@@ -0,0 +1,276 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/theplant/validator/proto"
+)
+
+// ErrValidation matches all validation errors by errors.Is,
+// like errors.Is(err, ErrValidation) is true if err is or wraps Errors, Error, MapError, OrderedMapError or *proto.Error.
+var ErrValidation = proto.ErrValidation
+
+// Error returns the error string like "lte=20 of Name: Name is too long".
+func (ve Error) Error() string {
+	tag := ve.Tag
+	if ve.Param != "" {
+		tag = fmt.Sprintf("%v=%v", ve.Tag, ve.Param)
+	}
+
+	if ve.Message == "" {
+		return fmt.Sprintf("%v of %v", tag, ve.Field)
+	}
+
+	return fmt.Sprintf("%v of %v: %v", tag, ve.Field, ve.Message)
+}
+
+// Unwrap returns Err of the Rule, so errors.Is and errors.As can find it.
+func (ve Error) Unwrap() error {
+	return ve.Err
+}
+
+// Is reports whether target is ErrValidation.
+func (ve Error) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// IsValidationError is the marker of the validation errors, see IsValidationError func.
+func (ve Error) IsValidationError() {}
+
+// Unwrap returns each Error of ves, so errors.Is and errors.As can find Err of the Rules,
+// like errors.Is(verrs, ErrName) is true if any Error has Err ErrName.
+func (ves Errors) Unwrap() []error {
+	errs := make([]error, 0, len(ves))
+	for _, ve := range ves {
+		errs = append(errs, ve)
+	}
+
+	return errs
+}
+
+// Is reports whether target is ErrValidation.
+func (ves Errors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// IsValidationError is the marker of the validation errors, see IsValidationError func.
+func (ves Errors) IsValidationError() {}
+
+// Is reports whether target is ErrValidation.
+func (vem MapError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// IsValidationError is the marker of the validation errors, see IsValidationError func.
+func (vem MapError) IsValidationError() {}
+
+// ByField returns the errors of the field path and its nested fields,
+// like ByField("Address") returns the errors of "Address", "Address.City" and "Address[0]".
+func (ves Errors) ByField(path string) Errors {
+	return ves.Filter(func(ve Error) bool {
+		return ve.Field == path || isNestedField(ve.Field, path)
+	})
+}
+
+func isNestedField(field string, path string) bool {
+	if path == "" || !strings.HasPrefix(field, path) {
+		return false
+	}
+
+	next := field[len(path)]
+	return next == '.' || next == '['
+}
+
+// HasField reports whether the field path or its nested fields have errors, see ByField.
+func (ves Errors) HasField(path string) bool {
+	return len(ves.ByField(path)) > 0
+}
+
+// HasTag reports whether any error is failed by tag, like "required".
+func (ves Errors) HasTag(tag string) bool {
+	for _, ve := range ves {
+		if ve.Tag == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fields returns the fields of the errors without duplicates, in the order of the errors.
+func (ves Errors) Fields() []string {
+	fields := []string{}
+	seen := map[string]bool{}
+	for _, ve := range ves {
+		if seen[ve.Field] {
+			continue
+		}
+		seen[ve.Field] = true
+		fields = append(fields, ve.Field)
+	}
+
+	return fields
+}
+
+// Filter returns the errors that fn returns true, it returns nil if no error is left.
+func (ves Errors) Filter(fn func(ve Error) bool) Errors {
+	var filtered Errors
+	for _, ve := range ves {
+		if fn(ve) {
+			filtered = append(filtered, ve)
+		}
+	}
+
+	return filtered
+}
+
+// Merge returns the errors of ves and others, it returns nil if all of them are empty.
+// ves is not changed.
+func (ves Errors) Merge(others ...Errors) Errors {
+	var merged Errors
+	merged = append(merged, ves...)
+	for _, other := range others {
+		merged = append(merged, other...)
+	}
+
+	return merged
+}
+
+// WithPrefix returns the errors that prefix is added to Field,
+// it is used to nest the errors of a sub-validation,
+// like WithPrefix("billing") or WithPrefix("billing.") changes "city" to "billing.city",
+// and "[0].sku" is changed to "billing[0].sku".
+func (ves Errors) WithPrefix(prefix string) Errors {
+	if len(ves) == 0 {
+		return nil
+	}
+
+	prefixed := make(Errors, len(ves))
+	for i, ve := range ves {
+		switch {
+		case ve.Field == "":
+			ve.Field = strings.TrimSuffix(prefix, ".")
+		case strings.HasPrefix(ve.Field, "["):
+			ve.Field = strings.TrimSuffix(prefix, ".") + ve.Field
+		default:
+			ve.Field = joinFieldName(strings.TrimSuffix(prefix, "."), ve.Field)
+		}
+		prefixed[i] = ve
+	}
+
+	return prefixed
+}
+
+// Dedupe returns the errors without duplicates, the errors with same Field, Tag and Param are duplicates,
+// the first one is kept.
+func (ves Errors) Dedupe() Errors {
+	type key struct {
+		field string
+		tag   string
+		param string
+	}
+
+	seen := map[key]bool{}
+	return ves.Filter(func(ve Error) bool {
+		k := key{field: ve.Field, tag: ve.Tag, param: ve.Param}
+		if seen[k] {
+			return false
+		}
+		seen[k] = true
+		return true
+	})
+}
+
+// SortByStruct returns the errors sorted by the order of the fields declared in the struct type of sample,
+// so the errors can be shown in the form order instead of the rule order.
+// The elements of slices are sorted by index, and the errors of the same field keep their order.
+//
+// sample can be a struct or a pointer to struct, tagName is the tagName used by DoRulesWithTagName.
+// The errors of the unknown fields are sorted after the others.
+func (ves Errors) SortByStruct(sample interface{}, tagName string) Errors {
+	if len(ves) == 0 {
+		return nil
+	}
+
+	typ := reflect.TypeOf(sample)
+
+	orders := make(map[string][]int, len(ves))
+	for _, ve := range ves {
+		if _, ok := orders[ve.Field]; !ok {
+			orders[ve.Field] = fieldOrder(typ, ve.Field, tagName)
+		}
+	}
+
+	sorted := make(Errors, len(ves))
+	copy(sorted, ves)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return lessOrder(orders[sorted[i].Field], orders[sorted[j].Field])
+	})
+
+	return sorted
+}
+
+// fieldOrder returns the indexes of the segments of path in typ,
+// the unknown segment is math.MaxInt32.
+func fieldOrder(typ reflect.Type, path string, tagName string) []int {
+	segments, err := parsePath(path)
+	if err != nil {
+		return []int{math.MaxInt32}
+	}
+
+	order := make([]int, 0, len(segments))
+	for _, segment := range segments {
+		for typ != nil && typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ == nil {
+			order = append(order, math.MaxInt32)
+			continue
+		}
+
+		switch typ.Kind() {
+		case reflect.Struct:
+			index, fieldType, ok := structFieldByName(typ, segment.name, tagName)
+			if !ok || segment.bracket {
+				order = append(order, math.MaxInt32)
+				typ = nil
+				continue
+			}
+			// The promoted fields are ordered as the fields of the embedded structs.
+			order = append(order, index...)
+			typ = fieldType
+		case reflect.Slice, reflect.Array:
+			index, err := strconv.Atoi(segment.name)
+			if err != nil {
+				index = math.MaxInt32
+			}
+			order = append(order, index)
+			typ = typ.Elem()
+		case reflect.Map:
+			// The map keys keep the order of the errors.
+			order = append(order, 0)
+			typ = typ.Elem()
+		default:
+			order = append(order, math.MaxInt32)
+			typ = nil
+		}
+	}
+
+	return order
+}
+
+// lessOrder compares the orders by their indexes, the parent is less than its nested fields.
+func lessOrder(a []int, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return len(a) < len(b)
+}
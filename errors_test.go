@@ -0,0 +1,132 @@
+package validator_test
+
+import (
+	"errors"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+	"github.com/theplant/validator/proto"
+)
+
+func TestErrors_IsAndAs(t *testing.T) {
+	validate := validator.New()
+
+	verrs, err := validate.DoRules(info{Password: "long password"}, infoRules)
+	fatalassert.NoError(t, err)
+
+	wrappedErr := pkgerrors.Wrap(verrs, "save info failed")
+
+	for _, target := range []error{validator.ErrValidation, ErrName, ErrAge} {
+		if !errors.Is(wrappedErr, target) {
+			t.Fatalf("errors.Is(%v) should be true", target)
+		}
+	}
+	if errors.Is(wrappedErr, ErrPassword) {
+		t.Fatal("errors.Is(ErrPassword) should be false")
+	}
+
+	var verr validator.Error
+	if !errors.As(wrappedErr, &verr) {
+		t.Fatal("errors.As(Error) should be true")
+	}
+	fatalassert.Equal(t, "Name", verr.Field)
+	fatalassert.Equal(t, "required of Name: name", verr.Error())
+
+	var gotVerrs validator.Errors
+	if !errors.As(wrappedErr, &gotVerrs) {
+		t.Fatal("errors.As(Errors) should be true")
+	}
+	fatalassert.Equal(t, verrs, gotVerrs)
+}
+
+func TestIsValidationErrorWithValidationErrors(t *testing.T) {
+	for _, err := range []error{
+		validator.Errors{{Field: "Name", Tag: "required"}},
+		validator.Error{Field: "Name", Tag: "required"},
+		validator.MapError{"Name": {"can not be blank"}},
+		validator.OrderedMapError{{Field: "Name", Messages: []string{"can not be blank"}}},
+		&proto.Error{},
+	} {
+		wrappedErr := pkgerrors.Wrap(err, "wrapped")
+
+		if !validator.IsValidationError(wrappedErr) {
+			t.Fatalf("%T should be validation error", err)
+		}
+		if !errors.Is(wrappedErr, validator.ErrValidation) {
+			t.Fatalf("%T should match ErrValidation", err)
+		}
+	}
+
+	if errors.Is(errors.New("other"), validator.ErrValidation) {
+		t.Fatal("other error should not match ErrValidation")
+	}
+}
+
+func TestErrors_Query(t *testing.T) {
+	verrs := validator.Errors{
+		{Field: "Name", Tag: "required"},
+		{Field: "Address.City", Tag: "required"},
+		{Field: "Address.City", Tag: "lte", Param: "20"},
+		{Field: "AddressLine", Tag: "required"},
+		{Field: "Items[1].SKU", Tag: "required"},
+	}
+
+	fatalassert.Equal(t, validator.Errors{
+		{Field: "Address.City", Tag: "required"},
+		{Field: "Address.City", Tag: "lte", Param: "20"},
+	}, verrs.ByField("Address"))
+	fatalassert.Equal(t, validator.Errors{{Field: "Items[1].SKU", Tag: "required"}}, verrs.ByField("Items"))
+
+	fatalassert.Equal(t, true, verrs.HasField("Items[1]"))
+	fatalassert.Equal(t, false, verrs.HasField("Age"))
+	fatalassert.Equal(t, true, verrs.HasTag("lte"))
+	fatalassert.Equal(t, false, verrs.HasTag("min"))
+	fatalassert.Equal(t, []string{"Name", "Address.City", "AddressLine", "Items[1].SKU"}, verrs.Fields())
+
+	if filtered := verrs.Filter(func(ve validator.Error) bool { return ve.Tag == "min" }); filtered != nil {
+		t.Fatalf("should return nil, but got %v", filtered)
+	}
+}
+
+func TestErrors_MergeWithPrefixAndDedupe(t *testing.T) {
+	validate := validator.New()
+
+	verrs, err := validate.DoRules(user{Name: "name"}, userRules)
+	fatalassert.NoError(t, err)
+
+	itemVerrs, err := validate.DoRules([]orderItem{{SKU: "a"}, {}}, []validator.Rule{{Field: "[*].SKU", Tag: "required"}})
+	fatalassert.NoError(t, err)
+
+	addressVerrs, err := validate.DoRules(address{}, []validator.Rule{{Field: "City", Tag: "required"}})
+	fatalassert.NoError(t, err)
+
+	merged := verrs.Merge(itemVerrs.WithPrefix("Items"), addressVerrs.WithPrefix("Address.")).Dedupe()
+
+	fatalassert.Equal(t, []string{"Age", "Address.City", "Items[1].SKU"}, merged.Fields())
+	fatalassert.Equal(t, 3, len(merged))
+	fatalassert.Equal(t, validator.Errors(nil), validator.Errors{}.Merge(nil))
+}
+
+func TestErrors_SortByStruct(t *testing.T) {
+	verrs := validator.Errors{
+		{Field: "stock[2].sku", Tag: "required"},
+		{Field: "unknown", Tag: "required"},
+		{Field: "items[10].qty", Tag: "min"},
+		{Field: "items[2].sku", Tag: "required"},
+		{Field: "items", Tag: "gte"},
+		{Field: "items[2].qty", Tag: "min"},
+		{Field: "items[2].sku", Tag: "lte"},
+	}
+
+	fatalassert.Equal(t, validator.Errors{
+		{Field: "items", Tag: "gte"},
+		{Field: "items[2].sku", Tag: "required"},
+		{Field: "items[2].sku", Tag: "lte"},
+		{Field: "items[2].qty", Tag: "min"},
+		{Field: "items[10].qty", Tag: "min"},
+		{Field: "stock[2].sku", Tag: "required"},
+		{Field: "unknown", Tag: "required"},
+	}, verrs.SortByStruct(&order{}, "json"))
+}
@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/theplant/validator/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCStatus converts ves to a gRPC status with codes.InvalidArgument, see proto.Error.GRPCStatus,
+// so Errors can be returned by the gRPC handler directly.
+//
+// DefaultViewMsg of the field violations is rendered by the default templates,
+// use VErrorsToGRPCStatus to render it by the registered templates.
+func (ves Errors) GRPCStatus() *status.Status {
+	protoErr, err := verrsToProtoError(ves, []compiledTemplates{defaultTemplates})
+	if err != nil {
+		return status.New(codes.InvalidArgument, ves.Error())
+	}
+	if protoErr == nil {
+		return status.New(codes.OK, "")
+	}
+
+	return protoErr.GRPCStatus()
+}
+
+// VErrorsToGRPCStatus converts verrs to a gRPC status with codes.InvalidArgument,
+// DefaultViewMsg of the field violations is rendered by the templates of locale, see VErrorsToProtoErrorLocale.
+//
+// If verrs is empty, it returns nil.
+func (v *Validate) VErrorsToGRPCStatus(verrs Errors, locale string) (*status.Status, error) {
+	protoErr, err := v.VErrorsToProtoErrorLocale(verrs, locale)
+	if err != nil {
+		return nil, err
+	}
+	if protoErr == nil {
+		return nil, nil
+	}
+
+	return protoErr.GRPCStatus(), nil
+}
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor that converts
+// the Errors and proto.Error returned by the handler to gRPC status,
+// even if they are wrapped by errors.Wrap or fmt.Errorf with %w.
+//
+// DefaultViewMsg is rendered by the templates of the locale in the "accept-language" metadata.
+func (v *Validate) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, v.grpcError(ctx, err)
+	}
+}
+
+// StreamServerInterceptor is same as UnaryServerInterceptor, but for the stream handlers.
+func (v *Validate) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return v.grpcError(ss.Context(), handler(srv, ss))
+	}
+}
+
+func (v *Validate) grpcError(ctx context.Context, err error) error {
+	var verrs Errors
+	if errors.As(err, &verrs) {
+		st, convertErr := v.VErrorsToGRPCStatus(verrs, grpcLocale(ctx))
+		if convertErr != nil {
+			return status.Error(codes.Internal, convertErr.Error())
+		}
+		if st == nil {
+			return err
+		}
+		return st.Err()
+	}
+
+	var protoErr *proto.Error
+	if errors.As(err, &protoErr) && protoErr != nil {
+		return protoErr.GRPCStatus().Err()
+	}
+
+	return err
+}
+
+// grpcLocale returns the first language of the "accept-language" metadata, like "ja" for "ja,en;q=0.8".
+func grpcLocale(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	for _, value := range md.Get("accept-language") {
+		if lang := firstLanguage(value); lang != "" {
+			return lang
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,148 @@
+package validator_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+	"github.com/theplant/validator/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var grpcValidate = validator.New()
+
+var checkRules = []validator.Rule{
+	{Field: "Name", Tag: "required", Code: "name-required"},
+	{Field: "Age", Tag: "min=20"},
+}
+
+func checkUser(name string) error {
+	verrs, err := grpcValidate.DoRules(user{Name: name}, checkRules)
+	if err != nil {
+		return err
+	}
+	if verrs != nil {
+		return errors.Wrap(verrs, "check user failed")
+	}
+
+	return nil
+}
+
+var checkServiceDesc = grpc.ServiceDesc{
+	ServiceName: "validator.Check",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Unary",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := &wrapperspb.StringValue{}
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					if err := checkUser(req.(*wrapperspb.StringValue).Value); err != nil {
+						return nil, fmt.Errorf("unary: %w", err)
+					}
+					return in, nil
+				}
+				return interceptor(ctx, in, &grpc.UnaryServerInfo{FullMethod: "/validator.Check/Unary"}, handler)
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "Stream",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				in := &wrapperspb.StringValue{}
+				if err := stream.RecvMsg(in); err != nil {
+					return err
+				}
+				return checkUser(in.Value)
+			},
+			ClientStreams: true,
+			ServerStreams: true,
+		},
+	},
+}
+
+func dialCheckService(t *testing.T) *grpc.ClientConn {
+	lis := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(grpcValidate.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(grpcValidate.StreamServerInterceptor()),
+	)
+	server.RegisterService(&checkServiceDesc, struct{}{})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	fatalassert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestValidate_UnaryServerInterceptor(t *testing.T) {
+	conn := dialCheckService(t)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "ja-JP,en;q=0.8")
+	err := conn.Invoke(ctx, "/validator.Check/Unary", wrapperspb.String(""), &wrapperspb.StringValue{})
+
+	st, _ := status.FromError(err)
+	fatalassert.Equal(t, codes.InvalidArgument, st.Code())
+
+	protoErr, ok := proto.FromGRPCStatus(st)
+	if !ok {
+		t.Fatalf("should get proto.Error from %v", st)
+	}
+
+	fatalassert.Equal(t, "必須項目です", protoErr.DefaultViewMsg)
+	fatalassert.Equal(t, 2, len(protoErr.FieldViolations))
+	fatalassert.Equal(t, "name-required", protoErr.FieldViolations[0].Code)
+	fatalassert.Equal(t, "min", protoErr.FieldViolations[1].Code)
+	fatalassert.Equal(t, "小さすぎます（最小20）", protoErr.FieldViolations[1].DefaultViewMsg)
+}
+
+func TestValidate_StreamServerInterceptor(t *testing.T) {
+	conn := dialCheckService(t)
+
+	stream, err := conn.NewStream(context.Background(), &checkServiceDesc.Streams[0], "/validator.Check/Stream")
+	fatalassert.NoError(t, err)
+	fatalassert.NoError(t, stream.SendMsg(wrapperspb.String("name")))
+	fatalassert.NoError(t, stream.CloseSend())
+
+	err = stream.RecvMsg(&wrapperspb.StringValue{})
+
+	protoErr, ok := proto.FromGRPCStatus(status.Convert(err))
+	if !ok {
+		t.Fatalf("should get proto.Error from %v", err)
+	}
+
+	fatalassert.Equal(t, "is too small, minimum is 20", protoErr.DefaultViewMsg)
+	fatalassert.Equal(t, 1, len(protoErr.FieldViolations))
+	fatalassert.Equal(t, "Age", protoErr.FieldViolations[0].Field)
+}
+
+func TestErrors_GRPCStatus(t *testing.T) {
+	verrs := validator.Errors{{Field: "Name", Tag: "required"}}
+
+	st, ok := status.FromError(verrs)
+	if !ok {
+		t.Fatal("Errors should be converted to status")
+	}
+	fatalassert.Equal(t, codes.InvalidArgument, st.Code())
+	fatalassert.Equal(t, "can not be blank", st.Message())
+}
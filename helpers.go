@@ -1,6 +1,10 @@
 package validator
 
-import "reflect"
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
 
 // Return true when all fields is zero.
 // sIface must be pointer to struct.
@@ -24,9 +28,11 @@ func IsStructZero(sIface interface{}) bool {
 	return true
 }
 
+// IsValidationError reports whether err or any error it wraps has the IsValidationError marker method,
+// like Errors, Error, MapError, OrderedMapError and *proto.Error.
 func IsValidationError(err error) bool {
-	_, ok := err.(interface {
+	var marker interface {
 		IsValidationError()
-	})
-	return ok
+	}
+	return errors.As(err, &marker)
 }
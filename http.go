@@ -0,0 +1,222 @@
+package validator
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	protov1 "github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/theplant/validator/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// MediaTypeJSON is the media type of the proto.Error in JSON, it is the default.
+	MediaTypeJSON = "application/json"
+	// MediaTypeProtobuf is the media type of the proto.Error in protobuf binary.
+	MediaTypeProtobuf = "application/x-protobuf"
+	// MediaTypeMapJSON is the media type of the OrderedMapError in JSON, like {"name": ["can not be blank"]},
+	// it is friendly to fill the errors of the form fields.
+	MediaTypeMapJSON = "application/vnd.validator.map+json"
+)
+
+// HTTPHandlerFunc is a http.HandlerFunc that returns error, see HTTPHandler.
+type HTTPHandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// HTTPHandler returns a http.Handler that writes the validation error returned by h by WriteHTTPError,
+// the other errors are written as 500 Internal Server Error.
+func (v *Validate) HTTPHandler(h HTTPHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h(w, r)
+		if err == nil || v.WriteHTTPError(w, r, err) {
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	})
+}
+
+// WriteHTTPError writes err to w if it is Errors, MapError, OrderedMapError or *proto.Error,
+// even if it is wrapped by errors.Wrap or fmt.Errorf with %w, and returns true.
+// Otherwise it writes nothing and returns false.
+//
+// The status code is proto.Error.HTTPStatusCode, 422.
+// The body is negotiated by the Accept header of r, the supported media type with the highest q is used:
+// - MediaTypeProtobuf, "application/protobuf": proto.Error.Message() in protobuf binary,
+// - MediaTypeMapJSON: OrderedMapError in JSON, the fields are in the order of the errors,
+// - MediaTypeJSON or others: proto.Error.Message() in JSON.
+//
+// The messages of Errors are rendered by the templates of the Accept-Language header of r,
+// see VErrorsToMapLocale and VErrorsToProtoErrorLocale.
+func (v *Validate) WriteHTTPError(w http.ResponseWriter, r *http.Request, err error) bool {
+	var (
+		protoErr *proto.Error
+		mapErr   OrderedMapError
+		convErr  error
+	)
+
+	locale := firstLanguage(r.Header.Get("Accept-Language"))
+	mediaType := negotiateMediaType(r.Header.Get("Accept"))
+
+	var (
+		verrs           Errors
+		vem             MapError
+		ordered         OrderedMapError
+		wrappedProtoErr *proto.Error
+	)
+	switch {
+	case errors.As(err, &verrs):
+		if len(verrs) == 0 {
+			return false
+		}
+		if mediaType == MediaTypeMapJSON {
+			mapErr, convErr = v.VErrorsToOrderedMapLocale(verrs, locale)
+		} else {
+			protoErr, convErr = v.VErrorsToProtoErrorLocale(verrs, locale)
+		}
+	case errors.As(err, &vem):
+		if len(vem) == 0 {
+			return false
+		}
+		mapErr = vem.Ordered()
+		protoErr = mapErrorToProtoError(mapErr)
+	case errors.As(err, &ordered):
+		if len(ordered) == 0 {
+			return false
+		}
+		mapErr = ordered
+		protoErr = mapErrorToProtoError(mapErr)
+	case errors.As(err, &wrappedProtoErr):
+		if wrappedProtoErr == nil {
+			return false
+		}
+		protoErr = wrappedProtoErr
+		mapErr = protoErrorToMapError(wrappedProtoErr)
+	default:
+		return false
+	}
+
+	if convErr != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return true
+	}
+
+	var (
+		body    []byte
+		bodyErr error
+	)
+	switch mediaType {
+	case MediaTypeProtobuf:
+		body, bodyErr = protov1.Marshal(protoErr.Message())
+	case MediaTypeMapJSON:
+		body, bodyErr = json.Marshal(mapErr)
+	default:
+		body, bodyErr = protojson.MarshalOptions{UseProtoNames: true}.Marshal(protov1.MessageV2(protoErr.Message()))
+	}
+	if bodyErr != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return true
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(protoErr.HTTPStatusCode())
+	w.Write(body)
+
+	return true
+}
+
+// negotiateMediaType returns the supported media type with the highest q in accept,
+// the first one in accept if they have the same q, or MediaTypeJSON if none is supported.
+// The media types with q=0 are not acceptable.
+func negotiateMediaType(accept string) string {
+	mediaType := MediaTypeJSON
+	bestQ := 0.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		params := strings.Split(mediaRange, ";")
+
+		supported := ""
+		switch strings.ToLower(strings.TrimSpace(params[0])) {
+		case MediaTypeProtobuf, "application/protobuf":
+			supported = MediaTypeProtobuf
+		case MediaTypeMapJSON:
+			supported = MediaTypeMapJSON
+		case MediaTypeJSON, "*/*", "application/*":
+			supported = MediaTypeJSON
+		default:
+			continue
+		}
+
+		if q := mediaRangeQ(params[1:]); q > bestQ {
+			mediaType = supported
+			bestQ = q
+		}
+	}
+
+	return mediaType
+}
+
+// mediaRangeQ returns the q param of the media range, it is 1 if it is absent, and 0 if it is invalid.
+func mediaRangeQ(params []string) float64 {
+	for _, param := range params {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) != 2 || strings.ToLower(strings.TrimSpace(kv[0])) != "q" {
+			continue
+		}
+
+		q, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+		if err != nil || q < 0 || q > 1 {
+			return 0
+		}
+		return q
+	}
+
+	return 1
+}
+
+// firstLanguage returns the first language of the Accept-Language value, like "ja" for "ja,en;q=0.8".
+func firstLanguage(acceptLanguage string) string {
+	for _, lang := range strings.Split(acceptLanguage, ",") {
+		lang = strings.TrimSpace(strings.SplitN(lang, ";", 2)[0])
+		if lang != "" && lang != "*" {
+			return lang
+		}
+	}
+
+	return ""
+}
+
+// mapErrorToProtoError converts mapErr to proto.Error,
+// the messages are the DefaultViewMsg of the field violations.
+func mapErrorToProtoError(mapErr OrderedMapError) *proto.Error {
+	protoErr := &proto.Error{Msg: mapErr.Error()}
+	for _, fm := range mapErr {
+		for _, message := range fm.Messages {
+			protoErr.FieldViolations = append(protoErr.FieldViolations, &proto.ValidationError_FieldViolation{
+				Field:          fm.Field,
+				DefaultViewMsg: message,
+			})
+		}
+	}
+	if len(protoErr.FieldViolations) > 0 {
+		protoErr.DefaultViewMsg = protoErr.FieldViolations[0].DefaultViewMsg
+	}
+
+	return protoErr
+}
+
+// protoErrorToMapError converts protoErr to OrderedMapError, the messages are DefaultViewMsg, or Msg if it is empty.
+func protoErrorToMapError(protoErr *proto.Error) OrderedMapError {
+	mapErr := OrderedMapError{}
+	index := map[string]int{}
+	for _, fv := range protoErr.FieldViolations {
+		message := fv.DefaultViewMsg
+		if message == "" {
+			message = fv.Msg
+		}
+		mapErr = mapErr.add(index, fv.Field, message)
+	}
+
+	return mapErr
+}
@@ -0,0 +1,97 @@
+package validator_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	protov1 "github.com/golang/protobuf/proto"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+	"github.com/theplant/validator/proto"
+)
+
+func serveHTTPError(validate *validator.Validate, err error, header http.Header) *httptest.ResponseRecorder {
+	handler := validate.HTTPHandler(func(w http.ResponseWriter, r *http.Request) error {
+		return err
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/users", nil)
+	for key, values := range header {
+		r.Header[key] = values
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestValidate_HTTPHandler(t *testing.T) {
+	validate := validator.New()
+
+	verrs, err := validate.DoRules(user{}, []validator.Rule{{Field: "Name", Tag: "required", Code: "name-required"}})
+	fatalassert.NoError(t, err)
+	wrappedErr := pkgerrors.Wrap(verrs, "create user failed")
+
+	w := serveHTTPError(validate, wrappedErr, http.Header{"Accept-Language": {"ja"}})
+	fatalassert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	fatalassert.Equal(t, validator.MediaTypeJSON, w.Header().Get("Content-Type"))
+
+	var body map[string]interface{}
+	fatalassert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	fatalassert.Equal(t, "name-required", body["code"])
+	fatalassert.Equal(t, "必須項目です", body["default_view_msg"])
+
+	w = serveHTTPError(validate, wrappedErr, http.Header{"Accept": {"application/x-protobuf, application/json"}})
+	fatalassert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	fatalassert.Equal(t, validator.MediaTypeProtobuf, w.Header().Get("Content-Type"))
+
+	validationErr := &proto.ValidationError{}
+	fatalassert.NoError(t, protov1.Unmarshal(w.Body.Bytes(), validationErr))
+	fatalassert.Equal(t, "can not be blank", validationErr.DefaultViewMsg)
+	fatalassert.Equal(t, "Name", validationErr.FieldViolations[0].Field)
+
+	w = serveHTTPError(validate, wrappedErr, http.Header{"Accept": {validator.MediaTypeMapJSON}})
+	fatalassert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	fatalassert.Equal(t, `{"Name":["can not be blank"]}`, w.Body.String())
+
+	w = serveHTTPError(validate, wrappedErr, http.Header{"Accept": {"application/x-protobuf;q=0.1, application/json"}})
+	fatalassert.Equal(t, validator.MediaTypeJSON, w.Header().Get("Content-Type"))
+
+	w = serveHTTPError(validate, wrappedErr, http.Header{"Accept": {"application/json;q=0, application/x-protobuf;q=0.5, " + validator.MediaTypeMapJSON + ";q=0.5"}})
+	fatalassert.Equal(t, validator.MediaTypeProtobuf, w.Header().Get("Content-Type"))
+
+	w = serveHTTPError(validate, fmt.Errorf("create user: %w", verrs), http.Header{"Accept": {validator.MediaTypeMapJSON}})
+	fatalassert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	fatalassert.Equal(t, `{"Name":["can not be blank"]}`, w.Body.String())
+
+	w = serveHTTPError(validate, errors.New("db down"), nil)
+	fatalassert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = serveHTTPError(validate, nil, nil)
+	fatalassert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestValidate_WriteHTTPErrorWithMapErrorAndProtoError(t *testing.T) {
+	validate := validator.New()
+
+	w := serveHTTPError(validate, validator.MapError{"name": {"can not be blank"}}, nil)
+	fatalassert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var body map[string]interface{}
+	fatalassert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	fatalassert.Equal(t, "can not be blank", body["default_view_msg"])
+
+	protoErr := &proto.Error{
+		FieldViolations: []*proto.ValidationError_FieldViolation{{Field: "name", Code: "required", Msg: "name required"}},
+	}
+
+	w = serveHTTPError(validate, fmt.Errorf("update user: %w", protoErr), http.Header{"Accept": {validator.MediaTypeMapJSON}})
+	fatalassert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	fatalassert.Equal(t, `{"name":["name required"]}`, w.Body.String())
+}
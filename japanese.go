@@ -0,0 +1,268 @@
+package validator
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"golang.org/x/text/width"
+)
+
+// JapanesePrefectures are the names of the 47 prefectures of Japan,
+// in the order of the JIS X 0401 codes, the code of JapanesePrefectures[i] is i+1.
+var JapanesePrefectures = []string{
+	"北海道", "青森県", "岩手県", "宮城県", "秋田県", "山形県", "福島県",
+	"茨城県", "栃木県", "群馬県", "埼玉県", "千葉県", "東京都", "神奈川県",
+	"新潟県", "富山県", "石川県", "福井県", "山梨県", "長野県", "岐阜県",
+	"静岡県", "愛知県", "三重県", "滋賀県", "京都府", "大阪府", "兵庫県",
+	"奈良県", "和歌山県", "鳥取県", "島根県", "岡山県", "広島県", "山口県",
+	"徳島県", "香川県", "愛媛県", "高知県", "福岡県", "佐賀県", "長崎県",
+	"熊本県", "大分県", "宮崎県", "鹿児島県", "沖縄県",
+}
+
+// japaneseTemplateMap and japaneseJaTemplateMap are the templates of RegisterJapaneseValidations.
+var japaneseTemplateMap = TemplateMap{
+	"katakana":            "must be full-width katakana",
+	"hiragana":            "must be hiragana",
+	"fullwidth":           "must be full-width characters",
+	"halfwidth":           "must be half-width characters",
+	"phone_jp":            "invalid phone number format",
+	"mobile_phone_jp":     "invalid mobile phone number format, format is 090-1234-5678",
+	"landline_phone_jp":   "invalid landline phone number format, format is 03-1234-5678",
+	"prefecture_jp":       "invalid prefecture",
+	"prefecture_code_jp":  "invalid prefecture code",
+	"corporate_number_jp": "invalid corporate number",
+	"my_number_jp":        "invalid My Number",
+}
+
+var japaneseJaTemplateMap = TemplateMap{
+	"katakana":            "全角カタカナで入力してください",
+	"hiragana":            "ひらがなで入力してください",
+	"fullwidth":           "全角文字で入力してください",
+	"halfwidth":           "半角文字で入力してください",
+	"phone_jp":            "電話番号の形式が正しくありません",
+	"mobile_phone_jp":     "携帯電話番号の形式が正しくありません（例：090-1234-5678）",
+	"landline_phone_jp":   "固定電話番号の形式が正しくありません（例：03-1234-5678）",
+	"prefecture_jp":       "都道府県が正しくありません",
+	"prefecture_code_jp":  "都道府県コードが正しくありません",
+	"corporate_number_jp": "法人番号が正しくありません",
+	"my_number_jp":        "マイナンバーが正しくありません",
+}
+
+var (
+	mobilePhoneJPRegexp   = regexp.MustCompile(`^0[789]0(-\d{4}-\d{4}|\d{8})$`)
+	landlinePhoneJPRegexp = regexp.MustCompile(`^0(\d{1,4}-\d{1,4}-\d{4}|[1-9]\d{8})$`)
+	digitsRegexp          = regexp.MustCompile(`^\d+$`)
+)
+
+// RegisterJapaneseValidations registers the validations for Japan, they are optional,
+// and it registers the templates of them in English and Japanese,
+// they can be covered by RegisterLocaleTemplateMap, see VErrorsToMapLocale:
+// - katakana: full-width katakana, like "ヤマダ タロウ", "ー", "・" and the spaces are allowed
+// - hiragana: hiragana, like "やまだ たろう", "ー" and the spaces are allowed
+// - fullwidth: full-width characters, like "東京都１－２", the ambiguous width characters like "○" are allowed
+// - halfwidth: half-width characters, like "Tokyo 1-2" or "ﾔﾏﾀﾞ"
+// - mobile_phone_jp: mobile phone number starts with 070, 080 or 090, like "090-1234-5678" or "09012345678"
+// - landline_phone_jp: 10 digits phone number, like "03-1234-5678" or "0312345678"
+// - phone_jp: mobile_phone_jp or landline_phone_jp
+// - prefecture_jp: name of JapanesePrefectures, like "東京都"
+// - prefecture_code_jp: JIS X 0401 code, like "13" or 13 for 東京都, the string code must be 2 digits
+// - corporate_number_jp: 13 digits corporate number (法人番号) with the valid check digit
+// - my_number_jp: 12 digits individual number (マイナンバー) with the valid check digit, use Rule.Redact for it
+//
+// The values are not normalized, use Rule.Normalize like "nfkc" or "halfwidth" for the user input.
+func (v *Validate) RegisterJapaneseValidations() error {
+	regexpValidations := []struct {
+		tag          string
+		regexpString string
+	}{
+		{"katakana", `^[\x{30A1}-\x{30FF}\x{3000} ]+$`},
+		{"hiragana", `^[\x{3041}-\x{309F}\x{30FC}\x{3000} ]+$`},
+	}
+	for _, rv := range regexpValidations {
+		if err := v.RegisterRegexpValidation(rv.tag, rv.regexpString); err != nil {
+			return errors.Wrapf(err, "register regexp validation %v failed", rv.tag)
+		}
+	}
+
+	validations := []struct {
+		tag string
+		fn  func(validator.FieldLevel) bool
+	}{
+		{"fullwidth", validateFullwidth},
+		{"halfwidth", validateHalfwidth},
+		{"mobile_phone_jp", validateMobilePhoneJP},
+		{"landline_phone_jp", validateLandlinePhoneJP},
+		{"phone_jp", validatePhoneJP},
+		{"prefecture_jp", validatePrefectureJP},
+		{"prefecture_code_jp", validatePrefectureCodeJP},
+		{"corporate_number_jp", validateCorporateNumberJP},
+		{"my_number_jp", validateMyNumberJP},
+	}
+	for _, rv := range validations {
+		if err := v.RegisterValidation(rv.tag, rv.fn); err != nil {
+			return errors.Wrapf(err, "register validation %v failed", rv.tag)
+		}
+	}
+
+	if err := v.registerPackTemplateMap(defaultLocale, japaneseTemplateMap); err != nil {
+		return errors.Wrap(err, "register en templates failed")
+	}
+	if err := v.registerPackTemplateMap("ja", japaneseJaTemplateMap); err != nil {
+		return errors.Wrap(err, "register ja templates failed")
+	}
+
+	return nil
+}
+
+func validateFullwidth(fl validator.FieldLevel) bool {
+	return allRunes(fl.Field().String(), func(r rune) bool {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth, width.EastAsianAmbiguous:
+			return true
+		}
+		return false
+	})
+}
+
+func validateHalfwidth(fl validator.FieldLevel) bool {
+	return allRunes(fl.Field().String(), func(r rune) bool {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianNarrow, width.EastAsianHalfwidth:
+			return true
+		}
+		return false
+	})
+}
+
+// allRunes reports whether s is not empty and all runes of s satisfy f.
+func allRunes(s string, f func(r rune) bool) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if !f(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validateLandlinePhoneJP(fl validator.FieldLevel) bool {
+	return isLandlinePhoneJP(fl.Field().String())
+}
+
+// isLandlinePhoneJP reports whether s is 10 digits starts with 0,
+// the area code and the subscriber number can be separated by "-".
+func isLandlinePhoneJP(s string) bool {
+	if !landlinePhoneJPRegexp.MatchString(s) {
+		return false
+	}
+
+	digits := 0
+	for _, r := range s {
+		if r != '-' {
+			digits++
+		}
+	}
+
+	return digits == 10 && s[1] != '0'
+}
+
+func validateMobilePhoneJP(fl validator.FieldLevel) bool {
+	return mobilePhoneJPRegexp.MatchString(fl.Field().String())
+}
+
+func validatePhoneJP(fl validator.FieldLevel) bool {
+	s := fl.Field().String()
+	return mobilePhoneJPRegexp.MatchString(s) || isLandlinePhoneJP(s)
+}
+
+func validatePrefectureJP(fl validator.FieldLevel) bool {
+	s := fl.Field().String()
+	for _, name := range JapanesePrefectures {
+		if s == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validatePrefectureCodeJP(fl validator.FieldLevel) bool {
+	field := fl.Field()
+
+	var code int64
+	switch field.Kind() {
+	case reflect.String:
+		if len(field.String()) != 2 || !digitsRegexp.MatchString(field.String()) {
+			return false
+		}
+		code, _ = strconv.ParseInt(field.String(), 10, 64)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		code = field.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if field.Uint() > uint64(len(JapanesePrefectures)) {
+			return false
+		}
+		code = int64(field.Uint())
+	default:
+		return false
+	}
+
+	return code >= 1 && code <= int64(len(JapanesePrefectures))
+}
+
+// validateCorporateNumberJP checks the 13 digits corporate number,
+// the first digit is the check digit of the other 12 digits:
+// 9 - (sum of the digits multiplied by 1 or 2 alternately from the right, starting with 1) % 9.
+func validateCorporateNumberJP(fl validator.FieldLevel) bool {
+	s := fl.Field().String()
+	if len(s) != 13 || !digitsRegexp.MatchString(s) {
+		return false
+	}
+
+	sum := 0
+	for n := 1; n <= 12; n++ {
+		digit := int(s[13-n] - '0')
+		if n%2 == 1 {
+			sum += digit
+		} else {
+			sum += digit * 2
+		}
+	}
+
+	return int(s[0]-'0') == 9-sum%9
+}
+
+// validateMyNumberJP checks the 12 digits individual number,
+// the last digit is the check digit of the other 11 digits:
+// the sum of the nth digit from the right multiplied by n+1 if n <= 6, or n-5 if n >= 7,
+// the check digit is 0 if sum % 11 <= 1, otherwise 11 - sum % 11.
+func validateMyNumberJP(fl validator.FieldLevel) bool {
+	s := fl.Field().String()
+	if len(s) != 12 || !digitsRegexp.MatchString(s) {
+		return false
+	}
+
+	sum := 0
+	for n := 1; n <= 11; n++ {
+		digit := int(s[11-n] - '0')
+		if n <= 6 {
+			sum += digit * (n + 1)
+		} else {
+			sum += digit * (n - 5)
+		}
+	}
+
+	check := 0
+	if sum%11 > 1 {
+		check = 11 - sum%11
+	}
+
+	return int(s[11]-'0') == check
+}
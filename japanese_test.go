@@ -0,0 +1,148 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+func TestValidate_RegisterJapaneseValidations(t *testing.T) {
+	validate := validator.New()
+	fatalassert.NoError(t, validate.RegisterJapaneseValidations())
+
+	cases := []struct {
+		tag     string
+		valid   []interface{}
+		invalid []interface{}
+	}{
+		{
+			tag:     "katakana",
+			valid:   []interface{}{"ヤマダ タロウ", "ヤマダ　タロー", "ジョン・スミス"},
+			invalid: []interface{}{"", "やまだ", "ﾔﾏﾀﾞ", "山田", "Yamada"},
+		},
+		{
+			tag:     "hiragana",
+			valid:   []interface{}{"やまだ たろう", "やまだ　たろー"},
+			invalid: []interface{}{"", "ヤマダ", "山田", "yamada"},
+		},
+		{
+			tag:     "fullwidth",
+			valid:   []interface{}{"東京都１－２", "ヤマダ　タロウ", "○"},
+			invalid: []interface{}{"", "東京都1-2", "ﾔﾏﾀﾞ", "ヤマダ タロウ"},
+		},
+		{
+			tag:     "halfwidth",
+			valid:   []interface{}{"Tokyo 1-2", "ﾔﾏﾀﾞ ﾀﾛｳ"},
+			invalid: []interface{}{"", "東京", "１２３", "ヤマダ"},
+		},
+		{
+			tag:     "mobile_phone_jp",
+			valid:   []interface{}{"090-1234-5678", "08012345678", "070-1234-5678"},
+			invalid: []interface{}{"03-1234-5678", "060-1234-5678", "090-1234-567", "090-12345678", "０９０１２３４５６７８"},
+		},
+		{
+			tag:     "landline_phone_jp",
+			valid:   []interface{}{"03-1234-5678", "0312345678", "0123-45-6789", "06-6123-4567"},
+			invalid: []interface{}{"090-1234-5678", "0012345678", "03-1234-567", "3-1234-5678", "03-12345-6789"},
+		},
+		{
+			tag:     "phone_jp",
+			valid:   []interface{}{"090-1234-5678", "03-1234-5678"},
+			invalid: []interface{}{"", "1234", "+81-90-1234-5678"},
+		},
+		{
+			tag:     "prefecture_jp",
+			valid:   []interface{}{"北海道", "東京都", "沖縄県"},
+			invalid: []interface{}{"", "東京", "Tokyo"},
+		},
+		{
+			tag:     "prefecture_code_jp",
+			valid:   []interface{}{"01", "13", "47", 13, uint8(47)},
+			invalid: []interface{}{"", "1", "00", "48", 0, 48, uint(100), 13.0},
+		},
+		{
+			tag:     "corporate_number_jp",
+			valid:   []interface{}{"7000012050002", "8000020130001"},
+			invalid: []interface{}{"", "8000012050002", "700001205000", "700001205000a"},
+		},
+		{
+			tag:     "my_number_jp",
+			valid:   []interface{}{"123456789018", "000000000000"},
+			invalid: []interface{}{"", "123456789012", "12345678901", "1234-5678-9018"},
+		},
+	}
+
+	for _, c := range cases {
+		rules := []validator.Rule{{Field: "[value]", Tag: c.tag}}
+
+		for _, value := range c.valid {
+			verrs, err := validate.DoRules(map[string]interface{}{"value": value}, rules)
+			fatalassert.NoError(t, err)
+			if verrs != nil {
+				t.Fatalf("%v should be valid %v, but got %v", value, c.tag, verrs)
+			}
+		}
+
+		for _, value := range c.invalid {
+			verrs, err := validate.DoRules(map[string]interface{}{"value": value}, rules)
+			fatalassert.NoError(t, err)
+			if !verrs.HasTag(c.tag) {
+				t.Fatalf("%#v should be invalid %v", value, c.tag)
+			}
+		}
+	}
+}
+
+func TestValidate_JapaneseTemplates(t *testing.T) {
+	validate := validator.New()
+	fatalassert.NoError(t, validate.RegisterJapaneseValidations())
+
+	rules := []validator.Rule{
+		{Field: "[kana]", Tag: "katakana"},
+		{Field: "[phone]", Tag: "phone_jp", Normalize: "nfkc,hyphen"},
+		{Field: "[corporate_number]", Tag: "corporate_number_jp"},
+	}
+	data := map[string]interface{}{
+		"kana":             "やまだ",
+		"phone":            "０３ー１２３４ー５６７８",
+		"corporate_number": "1234567890123",
+	}
+
+	verrs, err := validate.DoRules(data, rules)
+	fatalassert.NoError(t, err)
+
+	mapErr, err := validate.VErrorsToMapLocale(verrs, "ja")
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.MapError{
+		"[kana]":             {"全角カタカナで入力してください"},
+		"[corporate_number]": {"法人番号が正しくありません"},
+	}, mapErr)
+
+	mapErr, err = validate.VErrorsToMap(verrs)
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.MapError{
+		"[kana]":             {"must be full-width katakana"},
+		"[corporate_number]": {"invalid corporate number"},
+	}, mapErr)
+}
+
+func TestValidate_JapaneseTemplatesAreRegisteredOnValidate(t *testing.T) {
+	verrs := validator.Errors{{Field: "Name", Tag: "katakana"}}
+
+	validate := validator.New()
+	mapErr, err := validate.VErrorsToMapLocale(verrs, "ja")
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.MapError{"Name": {"katakanaの検証に失敗しました"}}, mapErr)
+
+	fatalassert.NoError(t, validate.RegisterJapaneseValidations())
+	fatalassert.NoError(t, validate.RegisterLocaleTemplateMap("ja", validator.TemplateMap{"katakana": "カタカナで入力してください"}))
+
+	mapErr, err = validate.VErrorsToMapLocale(verrs, "ja-JP")
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.MapError{"Name": {"カタカナで入力してください"}}, mapErr)
+
+	mapErr, err = validate.VErrorsToMap(verrs)
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.MapError{"Name": {"must be full-width katakana"}}, mapErr)
+}
@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// labelTagName is the struct tag of the human name of the field, like `label:"First name"`.
+const labelTagName = "label"
+
+// LabelMap is a map that mean [Rule.Field]label,
+// like LabelMap{"Items[*].SKU": "Item SKU"}.
+type LabelMap map[string]string
+
+// RegisterLabelMap registers the human names of the fields, they are set to Error.Label,
+// and cover the label struct tag of the fields.
+//
+// If you register multiple times, the front will be covered.
+//
+// NOTES:
+// - this method is not thread-safe it is intended that these all be registered prior to any validation
+func (v *Validate) RegisterLabelMap(labelMap LabelMap) {
+	v.labels = LabelMap{}
+	for field, label := range labelMap {
+		v.labels[field] = label
+	}
+}
+
+// errorLabel returns Label of verr,
+// if it is empty, returns the humanized last name of Field,
+// like "First name" for "Users[0].FirstName".
+func errorLabel(verr Error) string {
+	if verr.Label != "" {
+		return verr.Label
+	}
+
+	name := verr.Field
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+
+	return humanize(name)
+}
+
+// humanize converts the CamelCase or snake_case name to words,
+// like "First name" for "FirstName" or "first_name",
+// and the acronyms are kept, like "User ID" for "UserID".
+func humanize(name string) string {
+	words := []string{}
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || unicode.IsSpace(r)
+	}) {
+		words = append(words, splitCamelCase(part)...)
+	}
+
+	for i, word := range words {
+		if isUpperWord(word) && len([]rune(word)) > 1 {
+			continue
+		}
+
+		runes := []rune(strings.ToLower(word))
+		if i == 0 {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		words[i] = string(runes)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// splitCamelCase splits "UserIDNumber" to "User", "ID", "Number".
+func splitCamelCase(s string) []string {
+	runes := []rune(s)
+	words := []string{}
+
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		if unicode.IsUpper(cur) && (!unicode.IsUpper(prev) || unicode.IsLower(next)) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	return append(words, string(runes[start:]))
+}
+
+func isUpperWord(word string) bool {
+	for _, r := range word {
+		if !unicode.IsUpper(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,81 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+type member struct {
+	FirstName string `json:"first_name" label:"Given name"`
+	Age       int    `json:"age"`
+	UserID    string `json:"user_id"`
+	Password  string `json:"password"`
+	Roles     []string
+}
+
+func TestValidate_DoRulesWithLabelsAndValues(t *testing.T) {
+	validate := validator.New()
+	validate.RegisterLabelMap(validator.LabelMap{"Age": "Your age"})
+
+	rules := []validator.Rule{
+		{Field: "FirstName", Tag: "required"},
+		{Field: "Age", Tag: "min=20"},
+		{Field: "UserID", Tag: "required"},
+		{Field: "Password", Tag: "min=8", Redact: true},
+	}
+
+	verrs, err := validate.DoRulesWithTagName(member{Age: 15, Password: "secret"}, rules, "json")
+	fatalassert.NoError(t, err)
+
+	wantVerrs := validator.Errors{
+		{Field: "first_name", Tag: "required", Label: "Given name", Value: ""},
+		{Field: "age", Tag: "min", Param: "20", Label: "Your age", Value: 15},
+		{Field: "user_id", Tag: "required", Value: ""},
+		{Field: "password", Tag: "min", Param: "8", Value: validator.RedactedValue},
+	}
+
+	fatalassert.Equal(t, wantVerrs, verrs)
+}
+
+func TestValidate_VErrorsToMapWithTemplateData(t *testing.T) {
+	validate := validator.New()
+
+	fatalassert.NoError(t, validate.RegisterTemplateMap(validator.TemplateMap{
+		"min":      "{{.Label}} must be at least {{.Param}} (you entered {{.Value}})",
+		"required": "{{.Label}} can not be blank [{{.Code}}]",
+		"oneof":    "{{humanize .Field}} must be one of {{join \", \" .Param}}",
+		"gte":      "{{.Label}} needs at least {{.Param}} {{plural .Param \"role\" \"roles\"}}",
+	}))
+
+	rules := []validator.Rule{
+		{Field: "FirstName", Tag: "required", Code: "name-blank"},
+		{Field: "Age", Tag: "min=20"},
+		{Field: "UserID", Tag: "required"},
+		{Field: "Password", Tag: "min=8", Redact: true},
+		{Field: "Roles", Tag: "gte=1"},
+	}
+
+	mapErr, err := validate.DoRulesAndToMapErrorWithTagName(member{Age: 15, Password: "secret"}, rules, "json")
+	fatalassert.NoError(t, err)
+
+	fatalassert.Equal(t, validator.MapError{
+		"first_name": {"Given name can not be blank [name-blank]"},
+		"age":        {"Age must be at least 20 (you entered 15)"},
+		"user_id":    {"User id can not be blank []"},
+		"password":   {"Password must be at least 8 (you entered [REDACTED])"},
+		"Roles":      {"Roles needs at least 1 role"},
+	}, mapErr)
+
+	verrs := validator.Errors{{Field: "Roles[0]", Tag: "oneof", Param: "admin editor"}}
+	mapErr, err = validate.VErrorsToMap(verrs)
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.MapError{"Roles[0]": {"Roles[0] must be one of admin, editor"}}, mapErr)
+
+	// The messages are plain text, the user input is not HTML escaped.
+	verrs = validator.Errors{{Field: "LastName", Tag: "min", Param: "8", Label: "Last name", Value: "O'Neil <b>"}}
+	mapErr, err = validate.VErrorsToMap(verrs)
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.MapError{"LastName": {"Last name must be at least 8 (you entered O'Neil <b>)"}}, mapErr)
+}
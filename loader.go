@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	RuleConfigFormatJSON = "json"
+	RuleConfigFormatYAML = "yaml"
+)
+
+// RuleConfig is the rules defined in a YAML or JSON file.
+//
+// For example:
+//
+//	tag_name: json
+//	rules:
+//	  - field: Name
+//	    tag: required,lte=20
+//	    code: name-invalid
+//	    message: invalid name
+//	  - field: Address.Zip
+//	    tag: zipcode_jp
+//	    normalize: trim,halfwidth
+//	    if:
+//	      - field: Country
+//	        tag: eq=JP
+type RuleConfig struct {
+	// TagName is optional, it is the tagName for DoRulesWithTagName.
+	TagName string           `json:"tag_name" yaml:"tag_name"`
+	Rules   []RuleDefinition `json:"rules" yaml:"rules"`
+}
+
+// RuleDefinition is a Rule that can be defined in the file.
+type RuleDefinition struct {
+	Field     string      `json:"field" yaml:"field"`
+	Tag       string      `json:"tag" yaml:"tag"`
+	Code      string      `json:"code" yaml:"code"`
+	Message   string      `json:"message" yaml:"message"`
+	If        []Condition `json:"if" yaml:"if"`
+	Normalize string      `json:"normalize" yaml:"normalize"`
+}
+
+// ParseRuleConfig parses data in format, format is RuleConfigFormatJSON or RuleConfigFormatYAML.
+// Unknown keys in data are errors, so typos in the file can be found early.
+func ParseRuleConfig(data []byte, format string) (*RuleConfig, error) {
+	config := RuleConfig{}
+
+	switch format {
+	case RuleConfigFormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&config); err != nil {
+			return nil, errors.Wrap(err, "decode json rule config failed")
+		}
+	case RuleConfigFormatYAML:
+		if err := yaml.UnmarshalStrict(data, &config); err != nil {
+			return nil, errors.Wrap(err, "decode yaml rule config failed")
+		}
+	default:
+		return nil, errors.Errorf("unsupported rule config format %v", format)
+	}
+
+	for i, def := range config.Rules {
+		if def.Field == "" {
+			return nil, errors.Errorf("field of rule %v can not be empty", i)
+		}
+	}
+
+	return &config, nil
+}
+
+// LoadRuleConfig reads and parses the file name from fsys,
+// fsys can be an embed.FS or os.DirFS.
+//
+// The format is decided by the extension of name,
+// ".json" is JSON, ".yaml" and ".yml" are YAML.
+func LoadRuleConfig(fsys fs.FS, name string) (*RuleConfig, error) {
+	format := ""
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		format = RuleConfigFormatJSON
+	case ".yaml", ".yml":
+		format = RuleConfigFormatYAML
+	default:
+		return nil, errors.Errorf("unsupported rule config file %v", name)
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read rule config file %v failed", name)
+	}
+
+	config, err := ParseRuleConfig(data, format)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse rule config file %v failed", name)
+	}
+
+	return config, nil
+}
+
+// ToRules converts the definitions to rules, they are ready for DoRulesWithTagName with TagName.
+func (c *RuleConfig) ToRules() []Rule {
+	rules := make([]Rule, 0, len(c.Rules))
+	for _, def := range c.Rules {
+		rules = append(rules, Rule{
+			Field:     def.Field,
+			Tag:       def.Tag,
+			Code:      def.Code,
+			Message:   def.Message,
+			If:        def.If,
+			Normalize: def.Normalize,
+		})
+	}
+
+	return rules
+}
@@ -0,0 +1,60 @@
+package validator_test
+
+import (
+	"embed"
+	"os"
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+//go:embed testdata
+var testdataFS embed.FS
+
+func TestLoadRuleConfig(t *testing.T) {
+	validate := validator.New()
+
+	a := account{AccountType: "business", Country: "JP"}
+
+	wantVerrs := validator.Errors{
+		{Field: "Company", Tag: "required", Code: "company-invalid", Message: "invalid company", Value: ""},
+		{Field: "Address.Zip", Tag: "zipcode_jp", Value: ""},
+	}
+
+	for _, name := range []string{"testdata/account_rules.yaml", "testdata/account_rules.json"} {
+		config, err := validator.LoadRuleConfig(testdataFS, name)
+		fatalassert.NoError(t, err)
+		fatalassert.Equal(t, "json", config.TagName)
+
+		verrs, err := validate.DoRulesWithTagName(a, config.ToRules(), config.TagName)
+		fatalassert.NoError(t, err)
+		fatalassert.Equal(t, wantVerrs, verrs)
+	}
+
+	config, err := validator.LoadRuleConfig(os.DirFS("testdata"), "account_rules.yaml")
+	fatalassert.NoError(t, err)
+	fatalassert.NoError(t, validate.CheckRules(account{}, config.ToRules()))
+}
+
+func TestParseRuleConfigWithError(t *testing.T) {
+	cases := []struct {
+		data   string
+		format string
+	}{
+		{data: `rules: [{field: Name, tga: required}]`, format: validator.RuleConfigFormatYAML},
+		{data: `{"rules": [{"field": "Name", "tga": "required"}]}`, format: validator.RuleConfigFormatJSON},
+		{data: `rules: [{tag: required}]`, format: validator.RuleConfigFormatYAML},
+		{data: `rules: []`, format: "toml"},
+	}
+
+	for _, c := range cases {
+		if _, err := validator.ParseRuleConfig([]byte(c.data), c.format); err == nil {
+			t.Fatalf("%v should return error", c.data)
+		}
+	}
+
+	if _, err := validator.LoadRuleConfig(testdataFS, "testdata/not_exist.yaml"); err == nil {
+		t.Fatal("should return error")
+	}
+}
@@ -0,0 +1,90 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+func TestValidate_VErrorsToMapLocale(t *testing.T) {
+	validate := validator.New()
+
+	fatalassert.NoError(t, validate.RegisterTemplateMap(validator.TemplateMap{"lt": "custom lt {{.Param}}"}))
+	fatalassert.NoError(t, validate.RegisterLocaleTemplateMap("ja_JP", validator.TemplateMap{"required": "入力してください"}))
+	fatalassert.NoError(t, validate.RegisterLocaleTemplateMap("en", validator.TemplateMap{"gt": "en gt {{.Param}}"}))
+
+	verrs := validator.Errors{
+		{Field: "Name", Tag: "required"},
+		{Field: "Age", Tag: "min", Param: "20"},
+		{Field: "Age", Tag: "lt", Param: "100"},
+		{Field: "Age", Tag: "gt", Param: "0"},
+		{Field: "IP", Tag: "ipv4"},
+	}
+
+	cases := []struct {
+		locale     string
+		wantMapErr validator.MapError
+	}{
+		{
+			locale: "ja-JP",
+			wantMapErr: validator.MapError{
+				"Name": {"入力してください"},
+				"Age":  {"小さすぎます（最小20）", "custom lt 100", "en gt 0"},
+				"IP":   {"ipv4の検証に失敗しました"},
+			},
+		},
+		{
+			locale: "ja",
+			wantMapErr: validator.MapError{
+				"Name": {"必須項目です"},
+				"Age":  {"小さすぎます（最小20）", "custom lt 100", "en gt 0"},
+				"IP":   {"ipv4の検証に失敗しました"},
+			},
+		},
+		{
+			locale: "en-US",
+			wantMapErr: validator.MapError{
+				"Name": {"can not be blank"},
+				"Age":  {"is too small, minimum is 20", "custom lt 100", "en gt 0"},
+				"IP":   {"validation failed with ipv4"},
+			},
+		},
+		{
+			locale: "fr",
+			wantMapErr: validator.MapError{
+				"Name": {"can not be blank"},
+				"Age":  {"is too small, minimum is 20", "custom lt 100", "en gt 0"},
+				"IP":   {"validation failed with ipv4"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		gotMapErr, err := validate.VErrorsToMapLocale(verrs, c.locale)
+		fatalassert.NoError(t, err)
+		fatalassert.Equal(t, c.wantMapErr, gotMapErr, c.locale)
+	}
+}
+
+func TestValidate_DoRulesAndToMapErrorLocale(t *testing.T) {
+	validate := validator.New()
+
+	gotMapErr, err := validate.DoRulesAndToMapErrorLocale(user{}, userRules, "ja")
+	fatalassert.NoError(t, err)
+
+	wantMapErr := validator.MapError{
+		"Name":         {"必須項目です"},
+		"Age":          {"小さすぎます（最小20）"},
+		"Address.City": {"必須項目です"},
+	}
+
+	fatalassert.Equal(t, wantMapErr, gotMapErr)
+
+	if err := validate.RegisterLocaleTemplateMap("", validator.TemplateMap{}); err == nil {
+		t.Fatal("should return error")
+	}
+	if err := validate.RegisterLocaleTemplateMap("ja", validator.TemplateMap{"required": "{{.invalid}}"}); err == nil {
+		t.Fatal("should return error")
+	}
+}
@@ -0,0 +1,115 @@
+package validator_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+func decodeJSON(t *testing.T, s string) interface{} {
+	var data interface{}
+	fatalassert.NoError(t, json.Unmarshal([]byte(s), &data))
+	return data
+}
+
+func TestValidate_DoRulesWithMap(t *testing.T) {
+	validate := validator.New()
+
+	data := decodeJSON(t, `{
+		"name": "",
+		"age": 15,
+		"address": {"city": "", "zip": "1234"},
+		"items": [{"sku": "a"}, {"sku": ""}, {}],
+		"note": null
+	}`)
+
+	rules := []validator.Rule{
+		{Field: "name", Tag: "required"},
+		{Field: "age", Tag: "min=20"},
+		{Field: "address.city", Tag: "required"},
+		{Field: "address.zip", Tag: "zipcode_jp"},
+		{Field: "address.country", Tag: "required"},
+		{Field: "address.country", Tag: "omitempty,len=2"},
+		{Field: "items[*].sku", Tag: "required"},
+		{Field: "note", Tag: "required"},
+		{Field: "billing.city", Tag: "required"},
+		{Field: "billing.lines[*]", Tag: "required"},
+	}
+
+	verrs, err := validate.DoRules(data, rules)
+	fatalassert.NoError(t, err)
+
+	wantVerrs := validator.Errors{
+		{Field: "name", Tag: "required", Value: ""},
+		{Field: "age", Tag: "min", Param: "20", Value: 15.0},
+		{Field: "address.city", Tag: "required", Value: ""},
+		{Field: "address.zip", Tag: "zipcode_jp", Value: "1234"},
+		{Field: "address.country", Tag: "required"},
+		{Field: "items[1].sku", Tag: "required", Value: ""},
+		{Field: "items[2].sku", Tag: "required"},
+		{Field: "note", Tag: "required"},
+		{Field: "billing.city", Tag: "required"},
+	}
+
+	fatalassert.Equal(t, wantVerrs, verrs)
+
+	mapErr, err := validate.DoRulesAndToMapError(data, rules[:2])
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.MapError{
+		"name": {"can not be blank"},
+		"age":  {"is too small, minimum is 20"},
+	}, mapErr)
+}
+
+func TestValidate_DoRulesWithSliceAndConditions(t *testing.T) {
+	validate := validator.New()
+
+	data := decodeJSON(t, `[{"type": "business", "company": ""}, {"type": "personal"}]`)
+
+	rules := []validator.Rule{
+		{Field: "[0].company", Tag: "required", If: []validator.Condition{{Field: "[0].type", Tag: "eq=business"}}},
+		{Field: "[1].company", Tag: "required", If: []validator.Condition{{Field: "[1].type", Tag: "eq=business"}}},
+	}
+
+	verrs, err := validate.DoRules(data, rules)
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.Errors{{Field: "[0].company", Tag: "required", Value: ""}}, verrs)
+}
+
+type labeledMeta struct {
+	Meta map[string]interface{} `label:"Metadata"`
+}
+
+func TestValidate_DoRulesWithMapLabels(t *testing.T) {
+	validate := validator.New()
+
+	fatalassert.NoError(t, validate.RegisterTemplateMap(validator.TemplateMap{"required": "{{.Label}} is required"}))
+
+	rules := []validator.Rule{
+		{Field: "Meta", Tag: "required"},
+		{Field: "Meta.owner", Tag: "required"},
+		{Field: "Meta.team.name", Tag: "required"},
+		{Field: "Meta[*]", Tag: "required"},
+	}
+
+	data := labeledMeta{Meta: map[string]interface{}{"team_lead": ""}}
+	verrs, err := validate.DoRules(data, rules)
+	fatalassert.NoError(t, err)
+
+	wantVerrs := validator.Errors{
+		{Field: "Meta.owner", Tag: "required"},
+		{Field: "Meta.team.name", Tag: "required"},
+		{Field: "Meta[team_lead]", Tag: "required", Value: ""},
+	}
+	fatalassert.Equal(t, wantVerrs, verrs)
+
+	mapErr, err := validate.VErrorsToMap(verrs)
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.MapError{
+		"Meta.owner":      {"Owner is required"},
+		"Meta.team.name":  {"Name is required"},
+		"Meta[team_lead]": {"Meta is required"},
+	}, mapErr)
+}
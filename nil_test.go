@@ -0,0 +1,121 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+type billingAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type patchUser struct {
+	Name    *string         `json:"name"`
+	Billing *billingAddress `json:"billing"`
+	Country string          `json:"country"`
+}
+
+func TestValidate_DoRulesWithNilPointers(t *testing.T) {
+	validate := validator.New()
+
+	rules := []validator.Rule{
+		{Field: "Name", Tag: "omitempty,lte=5"},
+		{Field: "Billing.City", Tag: "required"},
+		{Field: "Billing.Zip", Tag: "omitempty,zipcode_jp"},
+		{Field: "Country", Tag: "required"},
+	}
+
+	verrs, err := validate.DoRulesWithTagName(patchUser{}, rules, "json")
+	fatalassert.NoError(t, err)
+
+	wantVerrs := validator.Errors{
+		{Field: "billing.city", Tag: "required"},
+		{Field: "country", Tag: "required", Value: ""},
+	}
+
+	fatalassert.Equal(t, wantVerrs, verrs)
+
+	name := "too long name"
+	verrs, err = validate.DoRulesWithTagName(&patchUser{Name: &name, Billing: &billingAddress{City: "city", Zip: "1"}, Country: "JP"}, rules, "json")
+	fatalassert.NoError(t, err)
+
+	wantVerrs = validator.Errors{
+		{Field: "name", Tag: "lte", Param: "5", Value: "too long name"},
+		{Field: "billing.zip", Tag: "zipcode_jp", Value: "1"},
+	}
+
+	fatalassert.Equal(t, wantVerrs, verrs)
+}
+
+func TestValidate_DoRulesWithNilPointersWithoutOmitempty(t *testing.T) {
+	validate := validator.New()
+
+	rules := []validator.Rule{
+		{Field: "Name", Tag: "lte=5"},
+		{Field: "Billing.City", Tag: "email"},
+		{Field: "Billing.Zip", Tag: "required,zipcode_jp"},
+	}
+
+	verrs, err := validate.DoRulesWithTagName(patchUser{}, rules, "json")
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.Errors{{Field: "billing.zip", Tag: "required"}}, verrs)
+
+	name := "too long name"
+	verrs, err = validate.DoRulesWithTagName(patchUser{Name: &name, Billing: &billingAddress{City: "city", Zip: "1"}}, rules, "json")
+	fatalassert.NoError(t, err)
+
+	wantVerrs := validator.Errors{
+		{Field: "name", Tag: "lte", Param: "5", Value: "too long name"},
+		{Field: "billing.city", Tag: "email", Value: "city"},
+		{Field: "billing.zip", Tag: "zipcode_jp", Value: "1"},
+	}
+
+	fatalassert.Equal(t, wantVerrs, verrs)
+}
+
+func TestValidate_DoRulesWithNilPointersAndConditions(t *testing.T) {
+	validate := validator.New()
+
+	rules := []validator.Rule{
+		{Field: "Country", Tag: "required", If: []validator.Condition{{Field: "Billing.City", Tag: "required"}}},
+		{Field: "Billing.Zip", Tag: "required,zipcode_jp", If: []validator.Condition{{Field: "Country", Tag: "eq=JP"}}},
+		{Field: "Billing.NotExist", Tag: "required"},
+	}
+
+	verrs, err := validate.DoRules(patchUser{Country: "JP"}, rules[:2])
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.Errors{{Field: "Billing.Zip", Tag: "required"}}, verrs)
+
+	if _, err := validate.DoRules(patchUser{}, rules[2:]); err == nil {
+		t.Fatal("should return error for the invalid field through nil pointer")
+	}
+}
+
+type auditBase struct {
+	Name string `json:"name"`
+}
+
+type auditedUser struct {
+	*auditBase
+	Email string `json:"email"`
+}
+
+func TestValidate_DoRulesWithNilEmbeddedPointer(t *testing.T) {
+	validate := validator.New()
+
+	rules := []validator.Rule{
+		{Field: "Name", Tag: "required"},
+		{Field: "Email", Tag: "required"},
+	}
+
+	verrs, err := validate.DoRulesWithTagName(auditedUser{Email: "a@example.com"}, rules, "json")
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.Errors{{Field: "name", Tag: "required"}}, verrs)
+
+	verrs, err = validate.DoRulesWithTagName(auditedUser{auditBase: &auditBase{Name: "name"}, Email: "a@example.com"}, rules, "json")
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.Errors(nil), verrs)
+}
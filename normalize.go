@@ -0,0 +1,223 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeFunc transforms the string value of the field before its validation tags,
+// like trimming the spaces, see Rule.Normalize.
+type NormalizeFunc func(s string) string
+
+// builtinNormalizers are registered in New.
+var builtinNormalizers = map[string]NormalizeFunc{
+	"trim":           strings.TrimSpace,
+	"lower":          strings.ToLower,
+	"upper":          strings.ToUpper,
+	"nfkc":           norm.NFKC.String,
+	"collapse_space": collapseSpace,
+	"halfwidth":      toHalfwidth,
+	"hyphen":         normalizeHyphen,
+}
+
+// RegisterNormalizer adds a normalizer with the given name, it can be used in Rule.Normalize.
+//
+// The builtin normalizers are:
+// - trim: removes the leading and trailing spaces
+// - lower, upper: changes the case
+// - nfkc: Unicode NFKC normalization, like "１２３" to "123" and "ｶﾞ" to "ガ"
+// - collapse_space: trims and replaces the consecutive spaces with a single space
+// - halfwidth: changes the full-width ASCII characters and the ideographic space to half-width, like "１２３－４５６７" to "123-4567"
+// - hyphen: changes the hyphen-like characters to "-", like "‐", "−", "－" and "ー"
+//
+// NOTES:
+// - if the key already exists, the previous normalizer will be replaced.
+// - this method is not thread-safe it is intended that these all be registered prior to any validation
+func (v *Validate) RegisterNormalizer(name string, fn NormalizeFunc) error {
+	if name == "" {
+		return errors.New("name can not be empty")
+	}
+	if fn == nil {
+		return errors.New("fn can not be nil")
+	}
+
+	v.normalizers[name] = fn
+
+	return nil
+}
+
+// namedNormalizer is a normalizer of Rule.Normalize, name is used to report its panic.
+type namedNormalizer struct {
+	name string
+	fn   NormalizeFunc
+}
+
+// compileNormalizers gets the normalizers of the comma separated names.
+func (v *Validate) compileNormalizers(names string) ([]namedNormalizer, error) {
+	if names == "" {
+		return nil, nil
+	}
+
+	normalizers := []namedNormalizer{}
+	for _, name := range strings.Split(names, tagSeparator) {
+		name = strings.TrimSpace(name)
+		fn, ok := v.normalizers[name]
+		if !ok {
+			return nil, &RuleError{Tag: name, Kind: ErrInvalidTag, Err: errors.Errorf("normalizer %v is not registered", name)}
+		}
+		normalizers = append(normalizers, namedNormalizer{name: name, fn: fn})
+	}
+
+	return normalizers, nil
+}
+
+func callNormalizer(n namedNormalizer, s string) string {
+	defer guardPanic("normalizer " + n.name)
+	return n.fn(s)
+}
+
+// Normalize applies Rule.Normalize of rules to data and writes the normalized values back,
+// data must be a pointer or a map, the fields through a non-pointer struct in the map can't be written back.
+//
+// The normalizers are applied regardless of When and If of the rules,
+// and they only change the string values, including the pointer to string and the interface{} of string.
+//
+// DoRules validates the normalized values too, but never changes data,
+// so use Normalize before DoRules if you want to save the normalized values:
+//
+//	if err := validate.Normalize(&form, formRules); err != nil {
+//		return err
+//	}
+//	verrs, err := validate.DoRules(form, formRules)
+func (v *Validate) Normalize(data interface{}, rules []Rule) error {
+	return v.NormalizeWithTagName(data, rules, "")
+}
+
+// NormalizeWithTagName is same as Normalize, the fields of rules are mapped through the tagName.
+func (v *Validate) NormalizeWithTagName(data interface{}, rules []Rule, tagName string) error {
+	dataVal := reflect.ValueOf(data)
+	if dataVal.Kind() != reflect.Ptr && dataVal.Kind() != reflect.Map {
+		return invalidTargetError("data should be a pointer or a map to normalize")
+	}
+	if dataVal.Kind() == reflect.Ptr && dataVal.IsNil() {
+		return invalidTargetError("data can not be nil pointer")
+	}
+
+	val, err := dataValue(data)
+	if err != nil {
+		return err
+	}
+
+	fields := []fieldValue{}
+	for i, rule := range rules {
+		if rule.Normalize == "" || rule.Func != nil {
+			continue
+		}
+
+		cr, err := v.compileRule(rule)
+		if err != nil {
+			return ruleError(err, i, rule)
+		}
+
+		fields, err = normalizeRule(val, &cr, tagName, fields)
+		if err != nil {
+			return ruleError(err, i, rule)
+		}
+	}
+
+	return nil
+}
+
+// normalizeRule writes the values of cr normalized back, fields is reused to resolve the values.
+// The panic of the normalizers is returned as RuleError of ErrValidatorPanic.
+func normalizeRule(val reflect.Value, cr *compiledRule, tagName string, fields []fieldValue) (_ []fieldValue, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicRuleError(r)
+		}
+	}()
+
+	fields, err = cr.path.appendResolved(fields[:0], val, tagName)
+	if err != nil {
+		return fields, &RuleError{Kind: ErrInvalidRuleField, Err: err}
+	}
+
+	for _, field := range fields {
+		field.normalize(cr.normalizers, true)
+	}
+
+	return fields, nil
+}
+
+// normalize returns the value of fv normalized by normalizers, it is same as interfaceOrNil if fv is not a string.
+// If writeBack is true, the normalized value is set to fv if it can be set.
+func (fv fieldValue) normalize(normalizers []namedNormalizer, writeBack bool) interface{} {
+	if len(normalizers) == 0 {
+		return fv.interfaceOrNil()
+	}
+
+	val := fv.value
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.String {
+		return fv.interfaceOrNil()
+	}
+
+	s := val.String()
+	for _, n := range normalizers {
+		s = callNormalizer(n, s)
+	}
+	normalized := reflect.ValueOf(s).Convert(val.Type())
+
+	if writeBack && s != val.String() {
+		switch {
+		case val.CanSet():
+			val.SetString(s)
+		case fv.mapValue.IsValid():
+			fv.mapValue.SetMapIndex(fv.mapKey, normalized)
+		case fv.value.CanSet():
+			fv.value.Set(normalized)
+		}
+	}
+
+	if fv.value.Kind() == reflect.Ptr {
+		ptr := reflect.New(val.Type())
+		ptr.Elem().Set(normalized)
+		return ptr.Interface()
+	}
+
+	return normalized.Interface()
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func toHalfwidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '！' && r <= '～':
+			return r - '！' + '!'
+		case r == '　':
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+func normalizeHyphen(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '‐', '‑', '‒', '–', '—', '―', '−', '－', 'ー', 'ｰ':
+			return '-'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,114 @@
+package validator_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+type signUp struct {
+	Email    string
+	Nickname *string
+	Zipcode  string
+	Phones   []string
+	Attrs    map[string]interface{}
+}
+
+var signUpRules = []validator.Rule{
+	{Field: "Email", Tag: "required,simple_email", Normalize: "trim,lower"},
+	{Field: "Nickname", Tag: "required,lte=12", Normalize: "collapse_space"},
+	{Field: "Zipcode", Tag: "required,zipcode_jp", Normalize: "trim,halfwidth"},
+	{Field: "Phones[*]", Tag: "numeric", Normalize: "nfkc"},
+	{Field: "Attrs[color]", Tag: "required,oneof=red blue", Normalize: "trim,lower"},
+}
+
+func TestValidate_DoRulesWithNormalize(t *testing.T) {
+	validate := validator.New()
+
+	nickname := "  Taro   Yamada "
+	data := signUp{
+		Email:    " Taro@Example.COM ",
+		Nickname: &nickname,
+		Zipcode:  "　１２３－４５６７ ",
+		Phones:   []string{"０９０１２３４５６７８"},
+		Attrs:    map[string]interface{}{"color": " RED "},
+	}
+
+	verrs, err := validate.DoRules(data, signUpRules)
+	fatalassert.NoError(t, err)
+	if verrs != nil {
+		t.Fatalf("should pass, but got %v", verrs)
+	}
+	fatalassert.Equal(t, "　１２３－４５６７ ", data.Zipcode)
+
+	data.Zipcode = "１２３４５６７"
+	verrs, err = validate.DoRules(data, signUpRules)
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.Errors{
+		{Field: "Zipcode", Tag: "zipcode_jp", Value: "1234567"},
+	}, verrs)
+}
+
+func TestValidate_Normalize(t *testing.T) {
+	validate := validator.New()
+	fatalassert.NoError(t, validate.RegisterNormalizer("digits", func(s string) string {
+		return strings.ReplaceAll(s, "-", "")
+	}))
+
+	nickname := "  Taro   Yamada "
+	data := signUp{
+		Email:    " Taro@Example.COM ",
+		Nickname: &nickname,
+		Zipcode:  "　１２３－４５６７ ",
+		Phones:   []string{"０９０１２３４５６７８", "03-1234-5678"},
+		Attrs:    map[string]interface{}{"color": " RED ", "size": 10},
+	}
+
+	rules := append(signUpRules, validator.Rule{Field: "Phones[*]", Normalize: "digits"})
+	fatalassert.NoError(t, validate.Normalize(&data, rules))
+	fatalassert.Equal(t, signUp{
+		Email:    "taro@example.com",
+		Nickname: &nickname,
+		Zipcode:  "123-4567",
+		Phones:   []string{"09012345678", "0312345678"},
+		Attrs:    map[string]interface{}{"color": "red", "size": 10},
+	}, data)
+	fatalassert.Equal(t, "Taro Yamada", nickname)
+
+	attrs := map[string]string{"color": " Blue"}
+	fatalassert.NoError(t, validate.Normalize(attrs, []validator.Rule{{Field: "[color]", Normalize: "trim,lower"}}))
+	fatalassert.Equal(t, "blue", attrs["color"])
+
+	if err := validate.Normalize(data, signUpRules); !errors.Is(err, validator.ErrInvalidTarget) {
+		t.Fatalf("should return ErrInvalidTarget, but got %v", err)
+	}
+
+	_, err := validate.DoRules(data, []validator.Rule{{Field: "Email", Tag: "required", Normalize: "trim,unknown"}})
+	if !errors.Is(err, validator.ErrInvalidTag) {
+		t.Fatalf("should return ErrInvalidTag, but got %v", err)
+	}
+}
+
+func TestValidate_NormalizeWithPanic(t *testing.T) {
+	validate := validator.New()
+	fatalassert.NoError(t, validate.RegisterNormalizer("broken", func(s string) string {
+		panic("broken normalizer")
+	}))
+
+	rules := []validator.Rule{{Field: "Email", Tag: "required", Normalize: "trim,broken"}}
+	data := signUp{Email: "a@example.com"}
+
+	_, err := validate.DoRules(data, rules)
+	if !errors.Is(err, validator.ErrValidatorPanic) {
+		t.Fatalf("should return ErrValidatorPanic, but got %v", err)
+	}
+	fatalassert.Equal(t, "rule 0 (Field: Email, Tag: required): normalizer broken panicked: broken normalizer", err.Error())
+
+	err = validate.Normalize(&data, rules)
+	if !errors.Is(err, validator.ErrValidatorPanic) {
+		t.Fatalf("should return ErrValidatorPanic, but got %v", err)
+	}
+}
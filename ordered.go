@@ -0,0 +1,147 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FieldMessages is the messages of a field in OrderedMapError.
+type FieldMessages struct {
+	Field    string
+	Messages []string
+}
+
+// OrderedMapError is same as MapError, but keeps the order of the fields,
+// the fields are in the order of their first errors, it is the order of the rules.
+//
+// It is marshaled to a JSON object like MapError, but the keys are in the same order,
+// so the client can show the first error of the form first.
+type OrderedMapError []FieldMessages
+
+// Ordered returns OrderedMapError of vem, the fields are sorted.
+func (vem MapError) Ordered() OrderedMapError {
+	fields := make([]string, 0, len(vem))
+	for field := range vem {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	ordered := make(OrderedMapError, 0, len(fields))
+	for _, field := range fields {
+		ordered = append(ordered, FieldMessages{Field: field, Messages: vem[field]})
+	}
+
+	return ordered
+}
+
+// add appends message to the messages of field,
+// index is the position of the fields in ome, it is updated when a new field is added.
+func (ome OrderedMapError) add(index map[string]int, field string, message string) OrderedMapError {
+	if i, ok := index[field]; ok {
+		ome[i].Messages = append(ome[i].Messages, message)
+		return ome
+	}
+
+	index[field] = len(ome)
+	return append(ome, FieldMessages{Field: field, Messages: []string{message}})
+}
+
+// Get returns the messages of field.
+func (ome OrderedMapError) Get(field string) []string {
+	for _, fm := range ome {
+		if fm.Field == field {
+			return fm.Messages
+		}
+	}
+
+	return nil
+}
+
+// ToMap converts ome to MapError.
+func (ome OrderedMapError) ToMap() MapError {
+	vem := MapError{}
+	for _, fm := range ome {
+		vem[fm.Field] = append(vem[fm.Field], fm.Messages...)
+	}
+
+	return vem
+}
+
+// Error is same as MapError.Error, but in the order of ome.
+func (ome OrderedMapError) Error() string {
+	errStrs := make([]string, 0, len(ome))
+	for _, fm := range ome {
+		errStrs = append(errStrs, fm.Field+":"+fmtStringArray(fm.Messages))
+	}
+
+	return strings.Join(errStrs, " ")
+}
+
+// MarshalJSON marshals ome to a JSON object, the keys are in the order of ome.
+func (ome OrderedMapError) MarshalJSON() ([]byte, error) {
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, fm := range ome {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		field, err := json.Marshal(fm.Field)
+		if err != nil {
+			return nil, err
+		}
+		messages, err := json.Marshal(fm.Messages)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(field)
+		buf.WriteByte(':')
+		buf.Write(messages)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON unmarshals the JSON object to ome, the order of the keys is kept.
+func (ome *OrderedMapError) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	if token, err := dec.Token(); err != nil {
+		return err
+	} else if token != json.Delim('{') {
+		return errors.New("OrderedMapError should be a JSON object")
+	}
+
+	ordered := OrderedMapError{}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		var messages []string
+		if err := dec.Decode(&messages); err != nil {
+			return errors.Wrapf(err, "decode messages of %v failed", token)
+		}
+
+		ordered = append(ordered, FieldMessages{Field: token.(string), Messages: messages})
+	}
+
+	*ome = ordered
+
+	return nil
+}
+
+// Is reports whether target is ErrValidation.
+func (ome OrderedMapError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// IsValidationError is the marker of the validation errors, see IsValidationError func.
+func (ome OrderedMapError) IsValidationError() {}
@@ -0,0 +1,59 @@
+package validator_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+func TestMapError_ErrorIsSorted(t *testing.T) {
+	mapErr := validator.MapError{
+		"Name":    {"can not be blank"},
+		"Age":     {"is too small", "is invalid"},
+		"Address": {"can not be blank"},
+	}
+
+	for i := 0; i < 10; i++ {
+		fatalassert.Equal(t, `Address:["can not be blank"] Age:["is too small", "is invalid"] Name:["can not be blank"]`, mapErr.Error())
+	}
+}
+
+func TestValidate_VErrorsToOrderedMap(t *testing.T) {
+	validate := validator.New()
+
+	verrs, err := validate.DoRules(user{Age: 10}, []validator.Rule{
+		{Field: "Name", Tag: "required"},
+		{Field: "Age", Tag: "min=20"},
+		{Field: "Address.City", Tag: "required"},
+		{Field: "Name", Tag: "eq=admin"},
+	})
+	fatalassert.NoError(t, err)
+
+	ordered, err := validate.VErrorsToOrderedMap(verrs)
+	fatalassert.NoError(t, err)
+
+	fatalassert.Equal(t, validator.OrderedMapError{
+		{Field: "Name", Messages: []string{"can not be blank", "validation failed with eq=admin"}},
+		{Field: "Age", Messages: []string{"is too small, minimum is 20"}},
+		{Field: "Address.City", Messages: []string{"can not be blank"}},
+	}, ordered)
+	fatalassert.Equal(t, []string{"is too small, minimum is 20"}, ordered.Get("Age"))
+
+	mapErr, err := validate.VErrorsToMap(verrs)
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, mapErr, ordered.ToMap())
+
+	data, err := json.Marshal(ordered)
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, `{"Name":["can not be blank","validation failed with eq=admin"],"Age":["is too small, minimum is 20"],"Address.City":["can not be blank"]}`, string(data))
+
+	var gotOrdered validator.OrderedMapError
+	fatalassert.NoError(t, json.Unmarshal(data, &gotOrdered))
+	fatalassert.Equal(t, ordered, gotOrdered)
+
+	if err := json.Unmarshal([]byte(`["Name"]`), &gotOrdered); err == nil {
+		t.Fatal("should return error when it is not a JSON object")
+	}
+}
@@ -0,0 +1,138 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+	"runtime/debug"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+)
+
+// ErrValidatorPanic matches the RuleError of the panic in the user code,
+// like the functions registered by RegisterValidation, RegisterContextValidation,
+// RegisterStructValidation and RegisterNormalizer, or When and Func of the Rule.
+//
+// The panic of go-playground/validator, like the tag is not registered
+// or the param of the tag is invalid, matches ErrInvalidTag instead,
+// and the unexpected panic of this package matches neither of them.
+var ErrValidatorPanic = errors.New("validator panic")
+
+// validatorPanic is the panic value of the user code, it is re-panicked by guardPanic,
+// so it can be told apart from the panic of go-playground/validator.
+type validatorPanic struct {
+	name  string
+	value interface{}
+	stack []byte
+}
+
+// guardPanic re-panics the panic of the user code called name as validatorPanic,
+// it must be deferred directly.
+func guardPanic(name string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if _, ok := r.(validatorPanic); ok {
+		panic(r)
+	}
+
+	panic(validatorPanic{name: name, value: r, stack: debug.Stack()})
+}
+
+// tagPanic is the panic value of go-playground/validator, it is re-panicked by guardTagPanic,
+// like the tag is not registered or the param of the tag is invalid.
+type tagPanic struct {
+	tag   string
+	value interface{}
+	stack []byte
+}
+
+// guardTagPanic re-panics the panic of go-playground/validator for tag as tagPanic,
+// validatorPanic is re-panicked as it is, it must be deferred directly.
+func guardTagPanic(tag string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if _, ok := r.(validatorPanic); ok {
+		panic(r)
+	}
+
+	panic(tagPanic{tag: tag, value: r, stack: debug.Stack()})
+}
+
+// gpVar is GPValidate.Var, but its panic is tagPanic.
+func (v *Validate) gpVar(field interface{}, tag string) error {
+	defer guardTagPanic(tag)
+	return v.GPValidate.Var(field, tag)
+}
+
+// gpVarWithValue is GPValidate.VarWithValue, but its panic is tagPanic.
+func (v *Validate) gpVarWithValue(field interface{}, other interface{}, tag string) error {
+	defer guardTagPanic(tag)
+	return v.GPValidate.VarWithValue(field, other, tag)
+}
+
+// panicRuleError converts the recovered value r to RuleError,
+// validatorPanic is ErrValidatorPanic, tagPanic is ErrInvalidTag,
+// Kind of the others is nil, they are the unexpected panics of this package.
+func panicRuleError(r interface{}) *RuleError {
+	switch p := r.(type) {
+	case validatorPanic:
+		var err error
+		if cause, ok := p.value.(error); ok {
+			err = errors.Wrapf(cause, "%v panicked", p.name)
+		} else {
+			err = errors.Errorf("%v panicked: %v", p.name, p.value)
+		}
+		return &RuleError{Kind: ErrValidatorPanic, Err: err, Stack: p.stack}
+
+	case tagPanic:
+		return &RuleError{Tag: p.tag, Kind: ErrInvalidTag, Err: errors.Errorf("%v", p.value), Stack: p.stack}
+	}
+
+	return &RuleError{Err: errors.Errorf("unexpected panic: %v", r), Stack: debug.Stack()}
+}
+
+func guardValidation(tag string, fn func(validator.FieldLevel) bool) func(validator.FieldLevel) bool {
+	if fn == nil {
+		return nil
+	}
+
+	return func(fl validator.FieldLevel) bool {
+		defer guardPanic("validation " + tag)
+		return fn(fl)
+	}
+}
+
+func callWhen(when func(data interface{}) bool, data interface{}) bool {
+	defer guardPanic("When")
+	return when(data)
+}
+
+func callContextFunc(ctx context.Context, ct contextTag, value interface{}) (bool, error) {
+	defer guardPanic("context validation " + ct.tag)
+	return ct.fn(ctx, value, ct.param)
+}
+
+func callStructFunc(name string, fn StructFunc, data interface{}) Errors {
+	defer guardPanic(name)
+	return fn(data)
+}
+
+// runStructFunc runs the struct validation registered for the type of val and maps its errors by structErrors,
+// the panic is returned as RuleError with Index -1.
+func runStructFunc(fn StructFunc, val reflect.Value, tagName string) (verrs Errors, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			re := panicRuleError(r)
+			re.Index = -1
+			err = re
+		}
+	}()
+
+	verrs = callStructFunc("struct validation of "+val.Type().String(), fn, val.Interface())
+
+	return structErrors(val, verrs, tagName, nil), nil
+}
@@ -0,0 +1,481 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const pathWildcard = "*"
+
+// pathSegment is one part of Rule.Field.
+//
+// For example "Items[*].Attrs[color]" will be parsed to
+// "Items", "[*]", "Attrs", "[color]".
+type pathSegment struct {
+	// name is the struct field name, or the index/key in the brackets.
+	name string
+	// bracket is true if the segment is written like "[2]", "[*]" or "[key]".
+	bracket bool
+}
+
+// fieldValue is a value resolved by a path,
+// name is the concrete path of the value, it is mapped through the tagName.
+//
+// value is invalid if the value is absent, like a missing key of the map.
+type fieldValue struct {
+	value reflect.Value
+	name  string
+	// label is the label tag of the last struct field in the path.
+	label string
+	// mapValue and mapKey are the map and the key of the value if it is a map element,
+	// the map element can't be set by value, it is set by mapValue.SetMapIndex.
+	mapValue reflect.Value
+	mapKey   reflect.Value
+}
+
+// interfaceOrNil returns nil if the value is absent, a nil pointer or a nil interface.
+func (fv fieldValue) interfaceOrNil() interface{} {
+	switch fv.value.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if fv.value.IsNil() {
+			return nil
+		}
+	}
+
+	return fv.value.Interface()
+}
+
+// parsePath parses path like "Address.City", "Items[2].Qty", "Items[*].SKU" or "Attrs[color]".
+func parsePath(path string) ([]pathSegment, error) {
+	segments := []pathSegment{}
+
+	start := 0
+	afterBracket := false
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '.':
+			if i == start && !afterBracket {
+				return nil, errors.Errorf("invalid field path %v: empty field name", path)
+			}
+			if i > start {
+				segments = append(segments, pathSegment{name: path[start:i]})
+			}
+			if i == len(path)-1 {
+				return nil, errors.Errorf("invalid field path %v: empty field name", path)
+			}
+			start = i + 1
+			afterBracket = false
+		case '[':
+			if i > start {
+				segments = append(segments, pathSegment{name: path[start:i]})
+			}
+			end := i + 1
+			for end < len(path) && path[end] != ']' {
+				end++
+			}
+			if end == len(path) {
+				return nil, errors.Errorf("invalid field path %v: unclosed bracket", path)
+			}
+			if end == i+1 {
+				return nil, errors.Errorf("invalid field path %v: empty brackets", path)
+			}
+			segments = append(segments, pathSegment{name: path[i+1 : end], bracket: true})
+			if end+1 < len(path) && path[end+1] != '.' && path[end+1] != '[' {
+				return nil, errors.Errorf("invalid field path %v: unexpected character after brackets", path)
+			}
+			i = end
+			start = end + 1
+			afterBracket = true
+		case ']':
+			return nil, errors.Errorf("invalid field path %v: unexpected ]", path)
+		}
+	}
+
+	if start < len(path) {
+		segments = append(segments, pathSegment{name: path[start:]})
+	}
+
+	if len(segments) == 0 {
+		return nil, errors.Errorf("invalid field path %v: empty path", path)
+	}
+
+	return segments, nil
+}
+
+// fieldPath is a parsed Rule.Field.
+type fieldPath struct {
+	path     string
+	segments []pathSegment
+}
+
+func newFieldPath(path string) (fieldPath, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return fieldPath{}, err
+	}
+
+	return fieldPath{path: path, segments: segments}, nil
+}
+
+// resolve gets all values of the path from val,
+// a wildcard segment is expanded over every element of the slice, array or map.
+//
+// If the tagName is found in a struct field, then use the tag value replace its name.
+func (p fieldPath) resolve(val reflect.Value, tagName string) ([]fieldValue, error) {
+	return p.appendResolved(nil, val, tagName)
+}
+
+// appendResolved is same as resolve, but appends the values to fields,
+// so the caller can reuse the slice.
+func (p fieldPath) appendResolved(fields []fieldValue, val reflect.Value, tagName string) ([]fieldValue, error) {
+	if err := resolveSegments(val, p.segments, "", "", tagName, &fields); err != nil {
+		return nil, errors.Wrapf(err, "get value from %v field failed", p.path)
+	}
+
+	return fields, nil
+}
+
+// resolveOne is same as resolve, but the path must resolve to exactly one value.
+func (p fieldPath) resolveOne(val reflect.Value, tagName string) (fieldValue, error) {
+	fields, err := p.resolve(val, tagName)
+	if err != nil {
+		return fieldValue{}, err
+	}
+
+	if len(fields) != 1 {
+		return fieldValue{}, errors.Errorf("get value from %v field failed: path must resolve to exactly one value", p.path)
+	}
+
+	return fields[0], nil
+}
+
+type structFieldKey struct {
+	typ     reflect.Type
+	name    string
+	tagName string
+}
+
+type structFieldInfo struct {
+	index []int
+	name  string
+	label string
+}
+
+// structFieldCache is a map that mean [structFieldKey]structFieldInfo.
+var structFieldCache sync.Map
+
+// cachedStructField gets the field index and the name mapped through the tagName of
+// the name field of typ, the result is cached per struct type.
+// If the field is not found or is unexported, it will return error, the unexported value can't be read.
+func cachedStructField(typ reflect.Type, name string, tagName string) (structFieldInfo, error) {
+	key := structFieldKey{typ: typ, name: name, tagName: tagName}
+	if info, ok := structFieldCache.Load(key); ok {
+		return info.(structFieldInfo), nil
+	}
+
+	sf, ok := typ.FieldByName(name)
+	if !ok {
+		return structFieldInfo{}, errors.Errorf("field %v not found in %v", name, typ)
+	}
+	if sf.PkgPath != "" {
+		return structFieldInfo{}, errors.Errorf("field %v of %v is unexported", name, typ)
+	}
+
+	info := structFieldInfo{index: sf.Index, name: getTagValue(sf, tagName), label: sf.Tag.Get(labelTagName)}
+	if info.name == "" {
+		info.name = name
+	}
+	structFieldCache.Store(key, info)
+
+	return info, nil
+}
+
+func resolveSegments(val reflect.Value, segments []pathSegment, name string, label string, tagName string, fields *[]fieldValue) error {
+	if len(segments) == 0 {
+		*fields = append(*fields, fieldValue{value: val, name: name, label: label})
+		return nil
+	}
+
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			// The values of the remaining path through nil are absent.
+			var typ reflect.Type
+			if val.Kind() == reflect.Ptr {
+				typ = val.Type().Elem()
+			}
+			return appendAbsent(typ, segments, name, label, tagName, fields)
+		}
+		val = val.Elem()
+	}
+
+	segment := segments[0]
+
+	switch val.Kind() {
+	case reflect.Struct:
+		if segment.bracket {
+			return errors.Errorf("can not use [%v] on struct %v", segment.name, val.Type())
+		}
+
+		info, err := cachedStructField(val.Type(), segment.name, tagName)
+		if err != nil {
+			return err
+		}
+
+		fieldVal, ok := fieldByIndex(val, info.index)
+		if !ok {
+			// The promoted field through a nil embedded pointer is absent.
+			return appendAbsent(val.Type().FieldByIndex(info.index).Type, segments[1:], joinFieldName(name, info.name), info.label, tagName, fields)
+		}
+
+		return resolveSegments(fieldVal, segments[1:], joinFieldName(name, info.name), info.label, tagName, fields)
+
+	case reflect.Slice, reflect.Array:
+		if !segment.bracket {
+			return errors.Errorf("can not get field %v from %v", segment.name, val.Type())
+		}
+
+		if segment.name == pathWildcard {
+			for i := 0; i < val.Len(); i++ {
+				if err := resolveSegments(val.Index(i), segments[1:], joinIndexName(name, strconv.Itoa(i)), label, tagName, fields); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		i, err := strconv.Atoi(segment.name)
+		if err != nil {
+			return errors.Errorf("invalid index [%v] of %v", segment.name, val.Type())
+		}
+		if i < 0 {
+			return errors.Errorf("invalid index [%v] of %v", segment.name, val.Type())
+		}
+		if i >= val.Len() {
+			// The element past the end is absent, like a missing key of the map.
+			return appendAbsent(val.Type().Elem(), segments[1:], joinIndexName(name, segment.name), label, tagName, fields)
+		}
+
+		return resolveSegments(val.Index(i), segments[1:], joinIndexName(name, segment.name), label, tagName, fields)
+
+	case reflect.Map:
+		if segment.name == pathWildcard {
+			// The label of the map is not the label of its values, they are named by the keys.
+			keys := val.MapKeys()
+			sortMapKeys(keys)
+			for _, key := range keys {
+				keyName := joinIndexName(name, fmt.Sprint(key.Interface()))
+				if len(segments) == 1 {
+					*fields = append(*fields, fieldValue{value: val.MapIndex(key), name: keyName, mapValue: val, mapKey: key})
+					continue
+				}
+				if err := resolveSegments(val.MapIndex(key), segments[1:], keyName, "", tagName, fields); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		key, err := mapKey(val.Type().Key(), segment.name)
+		if err != nil {
+			return err
+		}
+
+		// If the key is not found, elem is invalid, it means the value is absent.
+		elem := val.MapIndex(key)
+		if segment.bracket {
+			name = joinIndexName(name, segment.name)
+		} else {
+			name = joinFieldName(name, segment.name)
+		}
+		if len(segments) == 1 {
+			*fields = append(*fields, fieldValue{value: elem, name: name, mapValue: val, mapKey: key})
+			return nil
+		}
+		return resolveSegments(elem, segments[1:], name, "", tagName, fields)
+
+	case reflect.Invalid:
+		return appendAbsent(nil, segments, name, label, tagName, fields)
+	}
+
+	return errors.Errorf("can not get %v from %v", segment.name, val.Kind())
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex, but it returns false
+// instead of panicking if an embedded pointer in the way is nil.
+func fieldByIndex(val reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				return reflect.Value{}, false
+			}
+			val = val.Elem()
+		}
+		val = val.Field(x)
+	}
+
+	return val, true
+}
+
+// appendAbsent appends the absent value of the remaining segments,
+// typ is the type of the absent value, it is used to map the names through the tagName,
+// and it is nil if the type is unknown, like a missing key of map[string]interface{}.
+//
+// A wildcard of absent value has no element, so nothing is appended.
+func appendAbsent(typ reflect.Type, segments []pathSegment, name string, label string, tagName string, fields *[]fieldValue) error {
+	for _, segment := range segments {
+		if segment.name == pathWildcard {
+			return nil
+		}
+
+		for typ != nil && typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		if typ != nil && typ.Kind() == reflect.Struct {
+			if segment.bracket {
+				return errors.Errorf("can not use [%v] on struct %v", segment.name, typ)
+			}
+
+			info, err := cachedStructField(typ, segment.name, tagName)
+			if err != nil {
+				return err
+			}
+
+			name = joinFieldName(name, info.name)
+			label = info.label
+			typ = typ.FieldByIndex(info.index).Type
+			continue
+		}
+
+		if segment.bracket {
+			name = joinIndexName(name, segment.name)
+		} else {
+			name = joinFieldName(name, segment.name)
+		}
+
+		// The elements of the slice keep its label, but the map values and the unknown values don't.
+		if typ == nil || typ.Kind() == reflect.Map {
+			label = ""
+		}
+
+		if typ != nil && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map) {
+			typ = typ.Elem()
+		} else {
+			typ = nil
+		}
+	}
+
+	*fields = append(*fields, fieldValue{name: name, label: label})
+
+	return nil
+}
+
+func joinFieldName(name string, fieldName string) string {
+	if name == "" {
+		return fieldName
+	}
+	return name + "." + fieldName
+}
+
+func joinIndexName(name string, index string) string {
+	return name + "[" + index + "]"
+}
+
+// mapKey converts the key string of the path to the key type of the map.
+func mapKey(typ reflect.Type, key string) (reflect.Value, error) {
+	keyVal := reflect.New(typ).Elem()
+
+	switch typ.Kind() {
+	case reflect.String:
+		keyVal.SetString(key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, errors.Errorf("invalid key [%v] of %v", key, typ)
+		}
+		keyVal.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(key, 10, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, errors.Errorf("invalid key [%v] of %v", key, typ)
+		}
+		keyVal.SetUint(u)
+	case reflect.Interface:
+		keyVal.Set(reflect.ValueOf(key))
+	default:
+		return reflect.Value{}, errors.Errorf("unsupported map key type %v", typ)
+	}
+
+	return keyVal, nil
+}
+
+// sortMapKeys makes the order of wildcard map values stable.
+func sortMapKeys(keys []reflect.Value) {
+	sort.Slice(keys, func(i, j int) bool {
+		switch keys[i].Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return keys[i].Int() < keys[j].Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return keys[i].Uint() < keys[j].Uint()
+		}
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+}
+
+// typeOfPath returns the type of the value that the path points to in typ.
+// It returns nil type if the type can't be known before validation,
+// like a path through interface{}.
+func typeOfPath(typ reflect.Type, p fieldPath) (reflect.Type, error) {
+	for _, segment := range p.segments {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		switch typ.Kind() {
+		case reflect.Interface:
+			return nil, nil
+
+		case reflect.Struct:
+			if segment.bracket {
+				return nil, errors.Errorf("field %v invalid: can not use [%v] on struct %v", p.path, segment.name, typ)
+			}
+
+			info, err := cachedStructField(typ, segment.name, "")
+			if err != nil {
+				return nil, errors.Wrapf(err, "field %v invalid", p.path)
+			}
+			typ = typ.FieldByIndex(info.index).Type
+
+		case reflect.Slice, reflect.Array:
+			if !segment.bracket {
+				return nil, errors.Errorf("field %v invalid: can not get field %v from %v", p.path, segment.name, typ)
+			}
+			if segment.name != pathWildcard {
+				if i, err := strconv.Atoi(segment.name); err != nil || i < 0 {
+					return nil, errors.Errorf("field %v invalid: invalid index [%v] of %v", p.path, segment.name, typ)
+				}
+			}
+			typ = typ.Elem()
+
+		case reflect.Map:
+			if segment.name != pathWildcard {
+				if _, err := mapKey(typ.Key(), segment.name); err != nil {
+					return nil, errors.Wrapf(err, "field %v invalid", p.path)
+				}
+			}
+			typ = typ.Elem()
+
+		default:
+			return nil, errors.Errorf("field %v invalid: can not get %v from %v", p.path, segment.name, typ)
+		}
+	}
+
+	return typ, nil
+}
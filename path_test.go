@@ -0,0 +1,111 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+type orderItem struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type order struct {
+	Items []orderItem         `json:"items"`
+	Attrs map[string]string   `json:"attrs"`
+	Stock map[int]*orderItem  `json:"stock"`
+	Notes [2]string           `json:"notes"`
+	Tags  map[string][]string `json:"tags"`
+}
+
+func TestValidate_DoRulesWithElementPaths(t *testing.T) {
+	validate := validator.New()
+
+	o := order{
+		Items: []orderItem{{SKU: "a", Qty: 1}, {Qty: 0}, {SKU: "c", Qty: 3}, {}},
+		Attrs: map[string]string{"color": ""},
+		Stock: map[int]*orderItem{10: {SKU: "x"}, 2: {}},
+		Tags:  map[string][]string{"b": {""}, "a": {"ok"}},
+	}
+
+	rules := []validator.Rule{
+		{Field: "Items[*].SKU", Tag: "required"},
+		{Field: "Items[2].Qty", Tag: "max=2"},
+		{Field: "Attrs[color]", Tag: "required"},
+		{Field: "Stock[*].SKU", Tag: "required"},
+		{Field: "Notes[1]", Tag: "required"},
+		{Field: "Tags[*][0]", Tag: "required"},
+	}
+
+	verrs, err := validate.DoRules(o, rules)
+	fatalassert.NoError(t, err)
+
+	wantVerrs := validator.Errors{
+		{Field: "Items[1].SKU", Tag: "required", Value: ""},
+		{Field: "Items[3].SKU", Tag: "required", Value: ""},
+		{Field: "Items[2].Qty", Tag: "max", Param: "2", Value: 3},
+		{Field: "Attrs[color]", Tag: "required", Value: ""},
+		{Field: "Stock[2].SKU", Tag: "required", Value: ""},
+		{Field: "Notes[1]", Tag: "required", Value: ""},
+		{Field: "Tags[b][0]", Tag: "required", Value: ""},
+	}
+
+	fatalassert.Equal(t, wantVerrs, verrs)
+}
+
+func TestValidate_DoRulesWithElementPathsAndTagName(t *testing.T) {
+	validate := validator.New()
+
+	o := order{Items: []orderItem{{SKU: "a"}, {}}}
+
+	verrs, err := validate.DoRulesWithTagName(o, []validator.Rule{{Field: "Items[*].SKU", Tag: "required"}}, "json")
+	fatalassert.NoError(t, err)
+
+	wantVerrs := validator.Errors{
+		{Field: "items[1].sku", Tag: "required", Value: ""},
+	}
+
+	fatalassert.Equal(t, wantVerrs, verrs)
+}
+
+func TestValidate_DoRulesWithIndexOutOfRange(t *testing.T) {
+	validate := validator.New()
+
+	o := order{Items: []orderItem{{SKU: "a"}}}
+
+	verrs, err := validate.DoRulesWithTagName(o, []validator.Rule{
+		{Field: "Items[3].SKU", Tag: "required"},
+		{Field: "Items[4].Qty", Tag: "omitempty,min=1"},
+		{Field: "Items[0].SKU", Tag: "required"},
+	}, "json")
+	fatalassert.NoError(t, err)
+
+	fatalassert.Equal(t, validator.Errors{
+		{Field: "items[3].sku", Tag: "required"},
+	}, verrs)
+}
+
+func TestValidate_DoRulesWithInvalidElementPaths(t *testing.T) {
+	validate := validator.New()
+
+	o := order{Items: []orderItem{{SKU: "a"}}}
+
+	for _, field := range []string{
+		"Items[",
+		"Items[]",
+		"Items[-1].SKU",
+		"Items[x].SKU",
+		"Items.SKU",
+		"Items[0]SKU",
+		"Name[0]",
+		".Items",
+		"Items[0].",
+	} {
+		_, err := validate.DoRules(o, []validator.Rule{{Field: field, Tag: "required"}})
+		if err == nil {
+			t.Fatalf("%v should return error", field)
+		}
+	}
+}
@@ -1,11 +1,16 @@
 package proto
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/golang/protobuf/proto"
 )
 
+// ErrValidation matches all validation errors by errors.Is,
+// like errors.Is(err, ErrValidation) is true if err is or wraps *Error.
+var ErrValidation = errors.New("validation error")
+
 type Error ValidationError
 
 func (err *Error) Message() proto.Message {
@@ -31,3 +36,11 @@ func (err *Error) HTTPStatusCode() int {
 func (err *Error) Error() string {
 	return "validation error"
 }
+
+// Is reports whether target is ErrValidation.
+func (err *Error) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// IsValidationError is the marker of the validation errors.
+func (err *Error) IsValidationError() {}
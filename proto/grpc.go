@@ -0,0 +1,83 @@
+package proto
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCStatus converts err to a gRPC status with codes.InvalidArgument,
+// so it can be returned by the gRPC handler directly.
+//
+// The details of the status are a google.rpc.BadRequest with the field violations,
+// the description is DefaultViewMsg, or Msg if it is empty,
+// and the ValidationError itself, so FromGRPCStatus can get all fields back.
+func (err *Error) GRPCStatus() *status.Status {
+	msg := err.DefaultViewMsg
+	if msg == "" {
+		msg = err.Msg
+	}
+	if msg == "" {
+		msg = err.Error()
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, fv := range err.FieldViolations {
+		description := fv.DefaultViewMsg
+		if description == "" {
+			description = fv.Msg
+		}
+
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fv.Field,
+			Description: description,
+		})
+	}
+
+	st, detailsErr := status.New(codes.InvalidArgument, msg).WithDetails(badRequest, err.Message())
+	if detailsErr != nil {
+		return status.New(codes.InvalidArgument, msg)
+	}
+
+	return st
+}
+
+// FromGRPCStatus converts the status returned by GRPCStatus back to Error.
+//
+// If st has no ValidationError detail, the Error is built from the google.rpc.BadRequest detail,
+// Msg of the field violations is the description.
+// It returns false if the code of st is not codes.InvalidArgument, or st has no above details.
+func FromGRPCStatus(st *status.Status) (*Error, bool) {
+	if st == nil || st.Code() != codes.InvalidArgument {
+		return nil, false
+	}
+
+	var badRequest *errdetails.BadRequest
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *ValidationError:
+			return &Error{
+				Code:            d.Code,
+				Msg:             d.Msg,
+				DefaultViewMsg:  d.DefaultViewMsg,
+				FieldViolations: d.FieldViolations,
+			}, true
+		case *errdetails.BadRequest:
+			badRequest = d
+		}
+	}
+
+	if badRequest == nil {
+		return nil, false
+	}
+
+	err := &Error{Msg: st.Message()}
+	for _, fv := range badRequest.FieldViolations {
+		err.FieldViolations = append(err.FieldViolations, &ValidationError_FieldViolation{
+			Field: fv.Field,
+			Msg:   fv.Description,
+		})
+	}
+
+	return err, true
+}
@@ -0,0 +1,61 @@
+package proto
+
+import (
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorGRPCStatus(t *testing.T) {
+	err := &Error{
+		Msg: "validation failed",
+		FieldViolations: []*ValidationError_FieldViolation{
+			{Field: "name", Code: "required", Msg: "name required", DefaultViewMsg: "can not be blank"},
+			{Field: "age", Code: "min", Param: "20", Msg: "age too small"},
+		},
+	}
+
+	st := err.GRPCStatus()
+	fatalassert.Equal(t, codes.InvalidArgument, st.Code())
+	fatalassert.Equal(t, "validation failed", st.Message())
+
+	var badRequest *errdetails.BadRequest
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.BadRequest); ok {
+			badRequest = d
+		}
+	}
+	if badRequest == nil {
+		t.Fatal("should have BadRequest detail")
+	}
+	fatalassert.Equal(t, "can not be blank", badRequest.FieldViolations[0].Description)
+	fatalassert.Equal(t, "age too small", badRequest.FieldViolations[1].Description)
+
+	gotErr, ok := FromGRPCStatus(st)
+	if !ok {
+		t.Fatal("should get Error back")
+	}
+	fatalassert.Equal(t, "validation failed", gotErr.Msg)
+	fatalassert.Equal(t, "20", gotErr.FieldViolations[1].Param)
+}
+
+func TestFromGRPCStatusWithBadRequestOnly(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "bad request").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "name", Description: "can not be blank"}},
+	})
+	fatalassert.NoError(t, err)
+
+	gotErr, ok := FromGRPCStatus(st)
+	if !ok {
+		t.Fatal("should get Error from BadRequest")
+	}
+	fatalassert.Equal(t, "bad request", gotErr.Msg)
+	fatalassert.Equal(t, "can not be blank", gotErr.FieldViolations[0].Msg)
+
+	if _, ok := FromGRPCStatus(status.New(codes.NotFound, "not found")); ok {
+		t.Fatal("should not get Error from other codes")
+	}
+}
@@ -0,0 +1,54 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+func TestValidate_RegisterRegexpValidationWithInvalidRegexp(t *testing.T) {
+	validate := validator.New()
+
+	if err := validate.RegisterRegexpValidation("phone", `^(\d{3}`); err == nil {
+		t.Fatal("should return error")
+	}
+
+	if err := validate.RegisterRegexpValidationWithOptions("phone", `\d+`, validator.RegexpOptions{MaxLength: -1}); err == nil {
+		t.Fatal("should return error")
+	}
+}
+
+func TestValidate_RegisterRegexpValidationWithOptions(t *testing.T) {
+	validate := validator.New()
+
+	fatalassert.NoError(t, validate.RegisterRegexpValidation("code", `[a-z]{3}`))
+	fatalassert.NoError(t, validate.RegisterRegexpValidationWithOptions("anchored_code", `[a-z]{3}`, validator.RegexpOptions{
+		Anchored:        true,
+		CaseInsensitive: true,
+		MaxLength:       5,
+	}))
+
+	cases := []struct {
+		tag   string
+		value string
+		want  bool
+	}{
+		{tag: "code", value: "1abc1", want: true},
+		{tag: "code", value: "ABC", want: false},
+		{tag: "anchored_code", value: "1abc1", want: false},
+		{tag: "anchored_code", value: "ABC", want: true},
+		{tag: "anchored_code", value: "abc|x", want: false},
+	}
+
+	for _, c := range cases {
+		if got := validate.IsVar(c.value, c.tag); got != c.want {
+			t.Fatalf("%v of %v should be %v", c.tag, c.value, c.want)
+		}
+	}
+
+	fatalassert.NoError(t, validate.RegisterRegexpValidationWithOptions("short", `.*`, validator.RegexpOptions{MaxLength: 3}))
+	if !validate.IsVar("あいう", "short") || validate.IsVar("abcd", "short") {
+		t.Fatal("MaxLength should count characters")
+	}
+}
@@ -0,0 +1,119 @@
+package validator
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// RuleSet describes how to compose a named rule set.
+//
+// The rules are composed in this order:
+// 1. rules of Includes, in order,
+// 2. Rules,
+// 3. Overrides replace Tag of all rules with the same Field,
+// 4. Removes remove all rules with the same Field.
+type RuleSet struct {
+	// Includes are names of registered rule sets.
+	Includes []string
+	Rules    []Rule
+	// Overrides is a map that mean [Field]Tag.
+	Overrides map[string]string
+	Removes   []string
+}
+
+// RegisterRuleSet registers rules under name,
+// then you can use DoRuleSet to validate data with these rules.
+//
+// If name is empty, it will return error.
+// If you register the same name multiple times, the front will be covered.
+func (v *Validate) RegisterRuleSet(name string, rules []Rule) error {
+	return v.RegisterComposedRuleSet(name, RuleSet{Rules: rules})
+}
+
+// RegisterComposedRuleSet registers the rules composed by set under name.
+//
+// Includes must be registered before, and every field of Overrides and Removes
+// must be found in the composed rules, otherwise it will return error.
+// The composed rules are resolved at registration,
+// so registering an included set again doesn't affect this set.
+func (v *Validate) RegisterComposedRuleSet(name string, set RuleSet) error {
+	if name == "" {
+		return errors.New("name can not be empty")
+	}
+
+	rules := []Rule{}
+	for _, include := range set.Includes {
+		includeRules, ok := v.ruleSets[include]
+		if !ok {
+			return errors.Errorf("included rule set %v is not registered", include)
+		}
+		rules = append(rules, includeRules...)
+	}
+	rules = append(rules, set.Rules...)
+
+	for field, tag := range set.Overrides {
+		found := false
+		for i := range rules {
+			if rules[i].Field == field {
+				rules[i].Tag = tag
+				found = true
+			}
+		}
+		if !found {
+			return errors.Errorf("override field %v not found in rule set %v", field, name)
+		}
+	}
+
+	for _, field := range set.Removes {
+		kept := []Rule{}
+		for _, rule := range rules {
+			if rule.Field != field {
+				kept = append(kept, rule)
+			}
+		}
+		if len(kept) == len(rules) {
+			return errors.Errorf("remove field %v not found in rule set %v", field, name)
+		}
+		rules = kept
+	}
+
+	v.ruleSets[name] = rules
+
+	return nil
+}
+
+// RuleSet returns a copy of the rules registered under name.
+func (v *Validate) RuleSet(name string) ([]Rule, error) {
+	rules, ok := v.ruleSets[name]
+	if !ok {
+		return nil, errors.Errorf("rule set %v is not registered", name)
+	}
+
+	return append([]Rule{}, rules...), nil
+}
+
+// RuleSetNames returns the sorted names of all registered rule sets.
+func (v *Validate) RuleSetNames() []string {
+	names := []string{}
+	for name := range v.ruleSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// DoRuleSet is same as DoRules, but uses the rules registered under name.
+func (v *Validate) DoRuleSet(data interface{}, name string) (Errors, error) {
+	return v.DoRuleSetWithTagName(data, name, "")
+}
+
+func (v *Validate) DoRuleSetWithTagName(data interface{}, name string, tagName string) (Errors, error) {
+	rules, ok := v.ruleSets[name]
+	if !ok {
+		return nil, errors.Errorf("rule set %v is not registered", name)
+	}
+
+	return v.DoRulesWithTagName(data, rules, tagName)
+}
@@ -0,0 +1,74 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+func TestValidate_DoRuleSet(t *testing.T) {
+	validate := validator.New()
+
+	fatalassert.NoError(t, validate.RegisterRuleSet("user.base", []validator.Rule{
+		{Field: "Name", Tag: "required"},
+		{Field: "Age", Tag: "min=20"},
+	}))
+	fatalassert.NoError(t, validate.RegisterComposedRuleSet("user.signup", validator.RuleSet{
+		Includes:  []string{"user.base"},
+		Rules:     []validator.Rule{{Field: "Address.City", Tag: "required"}},
+		Overrides: map[string]string{"Age": "min=18"},
+	}))
+	fatalassert.NoError(t, validate.RegisterComposedRuleSet("user.address", validator.RuleSet{
+		Includes: []string{"user.signup"},
+		Removes:  []string{"Name", "Age"},
+	}))
+
+	u := user{Age: 10}
+
+	verrs, err := validate.DoRuleSet(u, "user.base")
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.Errors{
+		{Field: "Name", Tag: "required", Value: ""},
+		{Field: "Age", Tag: "min", Param: "20", Value: 10},
+	}, verrs)
+
+	verrs, err = validate.DoRuleSet(u, "user.signup")
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.Errors{
+		{Field: "Name", Tag: "required", Value: ""},
+		{Field: "Age", Tag: "min", Param: "18", Value: 10},
+		{Field: "Address.City", Tag: "required", Value: ""},
+	}, verrs)
+
+	verrs, err = validate.DoRuleSet(u, "user.address")
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.Errors{
+		{Field: "Address.City", Tag: "required", Value: ""},
+	}, verrs)
+
+	fatalassert.Equal(t, []string{"user.address", "user.base", "user.signup"}, validate.RuleSetNames())
+}
+
+func TestValidate_RegisterComposedRuleSetWithError(t *testing.T) {
+	validate := validator.New()
+
+	fatalassert.NoError(t, validate.RegisterRuleSet("user.base", []validator.Rule{{Field: "Name", Tag: "required"}}))
+
+	sets := map[string]validator.RuleSet{
+		"":             {},
+		"not included": {Includes: []string{"user.unknown"}},
+		"not override": {Includes: []string{"user.base"}, Overrides: map[string]string{"Age": "min=1"}},
+		"not remove":   {Includes: []string{"user.base"}, Removes: []string{"Age"}},
+	}
+
+	for name, set := range sets {
+		if err := validate.RegisterComposedRuleSet(name, set); err == nil {
+			t.Fatalf("%v should return error", name)
+		}
+	}
+
+	if _, err := validate.DoRuleSet(user{}, "user.unknown"); err == nil {
+		t.Fatal("should return error")
+	}
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// StructFunc is a struct level validation, it can check multiple fields together,
+// like "at least one of Email or Phone" or "StartAt before EndAt".
+//
+// It returns the Errors of the data, Field of the Error is the path like Rule.Field,
+// it will be mapped through the tagName if the path can be found in the data.
+type StructFunc func(data interface{}) Errors
+
+// RegisterStructValidation registers fn for the struct type of sample,
+// fn runs after all rules in every DoRules of the struct type,
+// and it gets the struct value even if DoRules gets a pointer to struct.
+//
+// sample can be a struct or a pointer to struct.
+// If you register multiple functions for the same type, they run in order.
+//
+// NOTES:
+// - this method is not thread-safe it is intended that these all be registered prior to any validation
+func (v *Validate) RegisterStructValidation(sample interface{}, fn StructFunc) error {
+	typ := reflect.TypeOf(sample)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return errors.New("sample should be a struct or a pointer to struct")
+	}
+	if fn == nil {
+		return errors.New("fn can not be nil")
+	}
+
+	v.structFuncs[typ] = append(v.structFuncs[typ], fn)
+
+	return nil
+}
+
+// structErrors maps Field of verrs through the tagName,
+// and fills the empty Code, Message and Err by rule if rule is not nil.
+func structErrors(val reflect.Value, verrs Errors, tagName string, rule *Rule) Errors {
+	for i := range verrs {
+		verr := &verrs[i]
+
+		if path, err := newFieldPath(verr.Field); err == nil {
+			if field, err := path.resolveOne(val, tagName); err == nil {
+				verr.Field = field.name
+			}
+		}
+
+		if rule == nil {
+			continue
+		}
+		if verr.Code == "" {
+			verr.Code = rule.Code
+		}
+		if verr.Message == "" {
+			verr.Message = rule.Message
+		}
+		if verr.Err == nil {
+			verr.Err = rule.Err
+		}
+	}
+
+	return verrs
+}
@@ -0,0 +1,119 @@
+package validator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+type invoiceItem struct {
+	Amount int `json:"amount"`
+}
+
+type invoice struct {
+	Email   string        `json:"email"`
+	Phone   string        `json:"phone"`
+	StartAt time.Time     `json:"start_at"`
+	EndAt   time.Time     `json:"end_at"`
+	Items   []invoiceItem `json:"items"`
+	Total   int           `json:"total"`
+}
+
+func TestValidate_DoRulesWithStructFunc(t *testing.T) {
+	validate := validator.New()
+
+	rules := []validator.Rule{
+		{Code: "contact-required", Message: "email or phone required", Func: func(data interface{}) validator.Errors {
+			inv := data.(invoice)
+			if inv.Email == "" && inv.Phone == "" {
+				return validator.Errors{{Field: "Email", Tag: "required_without", Param: "Phone"}}
+			}
+			return nil
+		}},
+		{Field: "Total", Tag: "min=1"},
+	}
+
+	verrs, err := validate.DoRulesWithTagName(&invoice{}, rules, "json")
+	fatalassert.NoError(t, err)
+
+	wantVerrs := validator.Errors{
+		{Field: "email", Tag: "required_without", Param: "Phone", Code: "contact-required", Message: "email or phone required"},
+		{Field: "total", Tag: "min", Param: "1", Value: 0},
+	}
+
+	fatalassert.Equal(t, wantVerrs, verrs)
+}
+
+func TestValidate_RegisterStructValidation(t *testing.T) {
+	validate := validator.New()
+
+	fatalassert.NoError(t, validate.RegisterStructValidation(invoice{}, func(data interface{}) validator.Errors {
+		inv := data.(invoice)
+		verrs := validator.Errors{}
+		if !inv.StartAt.Before(inv.EndAt) {
+			verrs = append(verrs, validator.Error{Field: "StartAt", Tag: "ltfield", Param: "EndAt", Code: "period-invalid"})
+		}
+		sum := 0
+		for _, item := range inv.Items {
+			sum += item.Amount
+		}
+		if sum != inv.Total {
+			verrs = append(verrs, validator.Error{Field: "Total", Tag: "sum", Param: "Items[*].Amount"})
+		}
+		return verrs
+	}))
+
+	now := time.Now()
+	inv := invoice{Email: "a@b", StartAt: now, EndAt: now.Add(-time.Hour), Items: []invoiceItem{{Amount: 1}, {Amount: 2}}, Total: 4}
+
+	for _, data := range []interface{}{inv, &inv} {
+		verrs, err := validate.DoRulesWithTagName(data, []validator.Rule{{Field: "Email", Tag: "required"}}, "json")
+		fatalassert.NoError(t, err)
+
+		wantVerrs := validator.Errors{
+			{Field: "start_at", Tag: "ltfield", Param: "EndAt", Code: "period-invalid"},
+			{Field: "total", Tag: "sum", Param: "Items[*].Amount"},
+		}
+
+		fatalassert.Equal(t, wantVerrs, verrs)
+	}
+
+	inv.EndAt = now.Add(time.Hour)
+	inv.Total = 3
+
+	verrs, err := validate.DoRules(inv, nil)
+	fatalassert.NoError(t, err)
+	if verrs != nil {
+		t.Fatalf("should not return Errors: %v", verrs)
+	}
+
+	if err := validate.RegisterStructValidation("not struct", func(interface{}) validator.Errors { return nil }); err == nil {
+		t.Fatal("should return error")
+	}
+}
+
+type invoiceParty struct {
+	Name string `json:"name"`
+}
+
+type partyInvoice struct {
+	*invoiceParty
+	Total int `json:"total"`
+}
+
+func TestValidate_RegisterStructValidationWithNilEmbeddedPointer(t *testing.T) {
+	validate := validator.New()
+
+	fatalassert.NoError(t, validate.RegisterStructValidation(partyInvoice{}, func(data interface{}) validator.Errors {
+		if data.(partyInvoice).invoiceParty == nil {
+			return validator.Errors{{Field: "Name", Tag: "required"}}
+		}
+		return nil
+	}))
+
+	verrs, err := validate.DoRulesWithTagName(&partyInvoice{Total: 1}, nil, "json")
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, validator.Errors{{Field: "name", Tag: "required"}}, verrs)
+}
@@ -33,9 +33,8 @@ func getTagAfter(tag string) string {
 // If get failed, then return "".
 // If tag value == "-", then return "".
 // If tag is "a,b,c", then return first value a.
-func getTagValue(val reflect.Value, name, tagName string) string {
-	field, ok := val.Type().FieldByName(name)
-	if !ok {
+func getTagValue(field reflect.StructField, tagName string) string {
+	if tagName == "" {
 		return ""
 	}
 
@@ -46,3 +45,50 @@ func getTagValue(val reflect.Value, name, tagName string) string {
 
 	return splitTag(tag)[0]
 }
+
+// structFieldByName returns the index sequence and type of the field of typ,
+// that name mapped through the tagName is name, like "city" for `json:"city"` if tagName is "json".
+// The fields promoted from the embedded structs are found too, the outer fields take precedence.
+func structFieldByName(typ reflect.Type, name string, tagName string) ([]int, reflect.Type, bool) {
+	if tagName == "" {
+		sf, ok := typ.FieldByName(name)
+		if !ok {
+			return nil, nil, false
+		}
+		return sf.Index, sf.Type, true
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+
+		fieldName := getTagValue(sf, tagName)
+		if fieldName == "" {
+			fieldName = sf.Name
+		}
+		if fieldName == name {
+			return []int{i}, sf.Type, true
+		}
+	}
+
+	// The embedded struct named by the tag is not promoted, like encoding/json.
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.Anonymous || getTagValue(sf, tagName) != "" {
+			continue
+		}
+
+		embeddedType := sf.Type
+		if embeddedType.Kind() == reflect.Ptr {
+			embeddedType = embeddedType.Elem()
+		}
+		if embeddedType.Kind() != reflect.Struct {
+			continue
+		}
+
+		if index, fieldType, ok := structFieldByName(embeddedType, name, tagName); ok {
+			return append([]int{i}, index...), fieldType, true
+		}
+	}
+
+	return nil, nil, false
+}
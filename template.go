@@ -0,0 +1,455 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+type TemplateMap map[string]string
+
+var defaultTemplateMap = TemplateMap{
+	"required":     "can not be blank",
+	"lte":          "is too long, maximum length is {{.Param}}",
+	"gte":          "is too short, minimum length is {{.Param}}",
+	"max":          "is too large, maximum is {{.Param}}",
+	"min":          "is too small, minimum is {{.Param}}",
+	"zipcode_jp":   "invalid zipcode format, format is 123-1234",
+	"inclusion":    "invalid {{.Param}} value",
+	"simple_email": "invalid email format",
+	"default":      "validation failed with {{ if eq .Param \"\" }}{{.Tag}}{{ else }}{{.Tag}}={{.Param}}{{ end }}",
+}
+
+var defaultJaTemplateMap = TemplateMap{
+	"required":     "必須項目です",
+	"lte":          "長すぎます（最大{{.Param}}文字）",
+	"gte":          "短すぎます（最小{{.Param}}文字）",
+	"max":          "大きすぎます（最大{{.Param}}）",
+	"min":          "小さすぎます（最小{{.Param}}）",
+	"zipcode_jp":   "郵便番号の形式が正しくありません（例：123-1234）",
+	"inclusion":    "{{.Param}}の値が正しくありません",
+	"simple_email": "メールアドレスの形式が正しくありません",
+	"default":      "{{ if eq .Param \"\" }}{{.Tag}}{{ else }}{{.Tag}}={{.Param}}{{ end }}の検証に失敗しました",
+}
+
+const defaultLocale = "en"
+
+// compiledTemplates is a map that mean [tag]template, it is parsed from a TemplateMap.
+type compiledTemplates map[string]*template.Template
+
+var defaultTemplates = mustCompileTemplateMap(defaultTemplateMap)
+
+// defaultLocaleTemplates is a map that mean [locale]templates.
+var defaultLocaleTemplates = map[string]compiledTemplates{
+	defaultLocale: defaultTemplates,
+	"ja":          mustCompileTemplateMap(defaultJaTemplateMap),
+}
+
+// templateCache is a map that mean [template string]*template.Template,
+// it is used by VErrorsToMap, so the same template is parsed only once.
+var templateCache sync.Map
+
+// templateValues are the variables can be used in the template.
+//
+// The messages are rendered by text/template, they are plain text for JSON, proto and logs,
+// Value and Param are the user input, so escape the messages in HTML views.
+type templateValues struct {
+	Param string
+	Tag   string
+	Field string
+	// Label is Error.Label, or the humanized field name if it is empty.
+	Label string
+	// Value is the rejected value, it is RedactedValue if Rule.Redact is true.
+	Value interface{}
+	Code  string
+}
+
+func newTemplateValues(verr Error) templateValues {
+	return templateValues{
+		Param: verr.Param,
+		Tag:   verr.Tag,
+		Field: verr.Field,
+		Label: errorLabel(verr),
+		Value: verr.Value,
+		Code:  verr.Code,
+	}
+}
+
+// templateFuncs are the functions can be used in the template:
+// - humanize converts the name to words, like {{humanize .Field}} is "First name" for "FirstName".
+// - join joins the list with sep, like {{join ", " .Param}} is "a, b" for "oneof=a b".
+// - plural returns singular if count is 1, like {{plural .Param "character" "characters"}}.
+var templateFuncs = template.FuncMap{
+	"humanize": humanize,
+	"join":     joinTemplateFunc,
+	"plural":   pluralTemplateFunc,
+}
+
+// joinTemplateFunc joins the list with sep,
+// list can be a slice, or a string that the items are separated by spaces.
+func joinTemplateFunc(sep string, list interface{}) string {
+	if str, ok := list.(string); ok {
+		return strings.Join(strings.Fields(str), sep)
+	}
+
+	val := reflect.ValueOf(list)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return fmt.Sprint(list)
+	}
+
+	items := make([]string, val.Len())
+	for i := range items {
+		items[i] = fmt.Sprint(val.Index(i).Interface())
+	}
+
+	return strings.Join(items, sep)
+}
+
+// pluralTemplateFunc returns singular if count is 1, otherwise returns plural,
+// count can be a number or a string of number like Param.
+func pluralTemplateFunc(count interface{}, singular string, plural string) string {
+	if n, err := strconv.ParseFloat(fmt.Sprint(count), 64); err == nil && n == 1 {
+		return singular
+	}
+
+	return plural
+}
+
+// compileTemplateMap parses all templates of templateMap,
+// the empty template is skipped, so the tag uses the default template.
+func compileTemplateMap(templateMap TemplateMap) (compiledTemplates, error) {
+	templates := compiledTemplates{}
+
+	for tag, tpl := range templateMap {
+		if tag == "" {
+			return nil, errors.New("tag of the templateMap can not be empty")
+		}
+		if tpl == "" {
+			continue
+		}
+
+		tl, err := parseTemplate(tpl)
+		if err != nil {
+			return nil, errors.Wrap(err, "tpl of the templateMap invalid")
+		}
+
+		templates[tag] = tl
+	}
+
+	return templates, nil
+}
+
+func mustCompileTemplateMap(templateMap TemplateMap) compiledTemplates {
+	templates, err := compileTemplateMap(templateMap)
+	if err != nil {
+		panic(err)
+	}
+
+	return templates
+}
+
+func getTemplate(tag string, customTemplateMap TemplateMap) string {
+	if message := customTemplateMap[tag]; message != "" {
+		return message
+	}
+
+	if message := defaultTemplateMap[tag]; message != "" {
+		return message
+	}
+
+	return defaultTemplateMap["default"]
+}
+
+func parseTemplate(templateStr string) (*template.Template, error) {
+	tl, err := template.New("validate").Funcs(templateFuncs).Parse(templateStr)
+	if err != nil {
+		return nil, errors.Wrap(err, "template parse failed")
+	}
+
+	return tl, nil
+}
+
+// cachedTemplate is same as parseTemplate, but the parsed template is cached.
+func cachedTemplate(templateStr string) (*template.Template, error) {
+	if tl, ok := templateCache.Load(templateStr); ok {
+		return tl.(*template.Template), nil
+	}
+
+	tl, err := parseTemplate(templateStr)
+	if err != nil {
+		return nil, err
+	}
+	templateCache.Store(templateStr, tl)
+
+	return tl, nil
+}
+
+func executeTemplate(tl *template.Template, tplValues templateValues) (string, error) {
+	message := bytes.Buffer{}
+	if err := tl.Execute(&message, tplValues); err != nil {
+		return "", errors.Wrap(err, "template execute failed")
+	}
+
+	return message.String(), nil
+}
+
+// templateMap is a map that mean [tag]template,
+// templateMap will be parsed by go template,
+// you can use ".Tag", ".Param", ".Field", ".Label", ".Value" and ".Code" variable,
+// and the "humanize", "join" and "plural" functions in the template, see templateFuncs.
+//
+// For example, validation tag is "max=100", then tag is "max", param is "100",
+// if template is "is too large, maximum is {{.Param}}",
+// then it will be parsed to "is too large, maximum is 100".
+// If template is "{{.Label}} must be at least {{.Param}} (you entered {{.Value}})",
+// then it will be parsed to "Age must be at least 20 (you entered 15)".
+//
+// If templateMap is nil, it will use defaultTemplateMap to parse.
+// If not found the tag in the templateMap, it will use defaultTemplateMap to parse for this tag,
+// it mean templateMap will merge to defaultTemplateMap,
+// so you don't worry about missing some tags of the templateMap.
+//
+// Each template is parsed only once, and cached for the following calls.
+//
+// If parse template failed, it will return error.
+func VErrorsToMap(verrs Errors, templateMap TemplateMap) (MapError, error) {
+	verrMap := MapError{}
+	for _, verr := range verrs {
+		tl, err := cachedTemplate(getTemplate(verr.Tag, templateMap))
+		if err != nil {
+			return nil, errors.Wrap(err, "parseTemplate failed")
+		}
+
+		vMessage, err := executeTemplate(tl, newTemplateValues(verr))
+		if err != nil {
+			return nil, errors.Wrap(err, "parseTemplate failed")
+		}
+		verrMap[verr.Field] = append(verrMap[verr.Field], vMessage)
+	}
+	return verrMap, nil
+}
+
+// VErrorsToMap is same as the VErrorsToMap func,
+// but uses the templates registered by RegisterTemplateMap, they are already parsed.
+func (v *Validate) VErrorsToMap(verrs Errors) (MapError, error) {
+	return v.VErrorsToMapLocale(verrs, "")
+}
+
+// VErrorsToMapLocale is same as VErrorsToMap, but uses the templates of locale.
+//
+// The template of a tag is looked up in the fallback chain of locale,
+// for example, the chain of "ja-JP" is:
+// 1. templates registered for "ja-JP" by RegisterLocaleTemplateMap,
+// 2. templates registered for "ja", then the "ja" templates of the validation packs
+// like RegisterJapaneseValidations, then the default "ja" templates,
+// 3. templates registered by RegisterTemplateMap,
+// 4. templates registered for "en", then the "en" templates of the validation packs, then the default "en" templates.
+// If the tag is not found in the chain, the "default" template is looked up in the same chain.
+//
+// If locale is "", only the 3rd and 4th steps are used.
+func (v *Validate) VErrorsToMapLocale(verrs Errors, locale string) (MapError, error) {
+	chain := v.templatesChain(locale)
+
+	vem := MapError{}
+	for _, verr := range verrs {
+		vMessage, err := executeTemplate(lookupTemplateInChain(verr.Tag, chain), newTemplateValues(verr))
+		if err != nil {
+			return nil, errors.Wrap(err, "executeTemplate failed")
+		}
+		vem[verr.Field] = append(vem[verr.Field], vMessage)
+	}
+	return vem, nil
+}
+
+// VErrorsToOrderedMap is same as VErrorsToMap, but returns OrderedMapError in the order of verrs.
+func (v *Validate) VErrorsToOrderedMap(verrs Errors) (OrderedMapError, error) {
+	return v.VErrorsToOrderedMapLocale(verrs, "")
+}
+
+// VErrorsToOrderedMapLocale is same as VErrorsToMapLocale, but returns OrderedMapError in the order of verrs.
+func (v *Validate) VErrorsToOrderedMapLocale(verrs Errors, locale string) (OrderedMapError, error) {
+	chain := v.templatesChain(locale)
+
+	ordered := OrderedMapError{}
+	index := map[string]int{}
+	for _, verr := range verrs {
+		vMessage, err := executeTemplate(lookupTemplateInChain(verr.Tag, chain), newTemplateValues(verr))
+		if err != nil {
+			return nil, errors.Wrap(err, "executeTemplate failed")
+		}
+		ordered = ordered.add(index, verr.Field, vMessage)
+	}
+	return ordered, nil
+}
+
+// RegisterLocaleTemplateMap parses all templates of templateMap once,
+// they will be used for locale and the locales fallback to it, see VErrorsToMapLocale.
+//
+// locale is case-insensitive, and "_" is same as "-", like "ja_JP" is same as "ja-JP".
+// If you register the same locale multiple times, the front will be covered.
+func (v *Validate) RegisterLocaleTemplateMap(locale string, templateMap TemplateMap) error {
+	locale = normalizeLocale(locale)
+	if locale == "" {
+		return errors.New("locale can not be empty")
+	}
+
+	templates, err := compileTemplateMap(templateMap)
+	if err != nil {
+		return err
+	}
+
+	if err := checkTemplates(templates); err != nil {
+		return err
+	}
+
+	v.localeTemplates[locale] = templates
+
+	return nil
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(strings.TrimSpace(locale), "_", "-", -1))
+}
+
+// localeChain returns locale and its parents, like "zh-hant-tw" returns "zh-hant-tw", "zh-hant", "zh".
+func localeChain(locale string) []string {
+	chain := []string{}
+	for locale = normalizeLocale(locale); locale != ""; {
+		chain = append(chain, locale)
+
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+
+	return chain
+}
+
+// templatesChain returns the templates in the fallback chain of locale.
+func (v *Validate) templatesChain(locale string) []compiledTemplates {
+	chain := []compiledTemplates{}
+	for _, l := range localeChain(locale) {
+		if l == defaultLocale {
+			break
+		}
+		chain = append(chain, v.localeTemplates[l], v.packTemplates[l], defaultLocaleTemplates[l])
+	}
+
+	return append(chain, v.customTemplates, v.localeTemplates[defaultLocale], v.packTemplates[defaultLocale], defaultLocaleTemplates[defaultLocale])
+}
+
+// registerPackTemplateMap adds the templates of a validation pack for locale,
+// they are merged with the templates of the other packs, and can be covered by RegisterLocaleTemplateMap.
+func (v *Validate) registerPackTemplateMap(locale string, templateMap TemplateMap) error {
+	templates, err := compileTemplateMap(templateMap)
+	if err != nil {
+		return err
+	}
+
+	if err := checkTemplates(templates); err != nil {
+		return err
+	}
+
+	locale = normalizeLocale(locale)
+	if v.packTemplates[locale] == nil {
+		v.packTemplates[locale] = compiledTemplates{}
+	}
+	for tag, tl := range templates {
+		v.packTemplates[locale][tag] = tl
+	}
+
+	return nil
+}
+
+func lookupTemplateInChain(tag string, chain []compiledTemplates) *template.Template {
+	for _, templates := range chain {
+		if tl := templates[tag]; tl != nil {
+			return tl
+		}
+	}
+
+	for _, templates := range chain {
+		if tl := templates["default"]; tl != nil {
+			return tl
+		}
+	}
+
+	return defaultTemplates["default"]
+}
+
+// RegisterTemplateMap parses all templates of templateMap once,
+// they will be used by DoRulesAndToMapError.
+func (v *Validate) RegisterTemplateMap(templateMap TemplateMap) error {
+	templates, err := compileTemplateMap(templateMap)
+	if err != nil {
+		return err
+	}
+
+	if err := checkTemplates(templates); err != nil {
+		return err
+	}
+
+	v.customTemplates = templates
+
+	return nil
+}
+
+// checkTemplates executes templates with the check values,
+// so the templates using invalid variables can be found.
+func checkTemplates(templates compiledTemplates) error {
+	tplValues := templateValues{
+		Param: "check param",
+		Tag:   "check tag",
+		Field: "check field",
+		Label: "check label",
+		Value: "check value",
+		Code:  "check code",
+	}
+
+	for _, tl := range templates {
+		if _, err := executeTemplate(tl, tplValues); err != nil {
+			return errors.Wrap(err, "tpl of the templateMap invalid")
+		}
+	}
+
+	return nil
+}
+
+// DoRulesAndToMapErrorLocale is same as DoRulesAndToMapError, but uses the templates of locale,
+// see VErrorsToMapLocale.
+func (v *Validate) DoRulesAndToMapErrorLocale(data interface{}, rules []Rule, locale string) (MapError, error) {
+	verrs, err := v.DoRules(data, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.VErrorsToMapLocale(verrs, locale)
+}
+
+// Same as DoRules, run DoRules and VErrorsToMap with custom template.
+// You can use RegisterTemplateMap func to register custom template.
+func (v *Validate) DoRulesAndToMapError(data interface{}, rules []Rule) (MapError, error) {
+	verrs, err := v.DoRules(data, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.VErrorsToMap(verrs)
+}
+
+func (v *Validate) DoRulesAndToMapErrorWithTagName(data interface{}, rules []Rule, tagName string) (MapError, error) {
+	verrs, err := v.DoRulesWithTagName(data, rules, tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.VErrorsToMap(verrs)
+}
@@ -0,0 +1,56 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/theplant/testingutils/fatalassert"
+	"github.com/theplant/validator"
+)
+
+func TestValidate_VErrorsToMap(t *testing.T) {
+	validate := validator.New()
+
+	fatalassert.NoError(t, validate.RegisterTemplateMap(validator.TemplateMap{
+		"required": "custom require",
+		"lte":      "",
+	}))
+
+	verrs := validator.Errors{
+		{Field: "Name", Tag: "required"},
+		{Field: "Name", Tag: "lte", Param: "20"},
+		{Field: "Age", Tag: "lt", Param: "20"},
+	}
+
+	wantMapErr := validator.MapError{
+		"Name": {"custom require", "is too long, maximum length is 20"},
+		"Age":  {"validation failed with lt=20"},
+	}
+
+	for i := 0; i < 2; i++ {
+		gotMapErr, err := validate.VErrorsToMap(verrs)
+		fatalassert.NoError(t, err)
+		fatalassert.Equal(t, wantMapErr, gotMapErr)
+	}
+}
+
+func TestVErrorsToMapWithCachedTemplates(t *testing.T) {
+	verrs := validator.Errors{{Field: "Name", Tag: "required"}}
+
+	for _, tpl := range []string{"first {{.Tag}}", "second {{.Tag}}", "first {{.Tag}}"} {
+		gotMapErr, err := validator.VErrorsToMap(verrs, validator.TemplateMap{"required": tpl})
+		fatalassert.NoError(t, err)
+
+		wantMapErr := validator.MapError{"Name": {tpl[:len(tpl)-len("{{.Tag}}")] + "required"}}
+		fatalassert.Equal(t, wantMapErr, gotMapErr)
+	}
+}
+
+func BenchmarkValidate_VErrorsToMap(b *testing.B) {
+	validate := validator.New()
+	verrs, _ := validate.DoRules(info{}, infoRules)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validate.VErrorsToMap(verrs)
+	}
+}
@@ -1,12 +1,12 @@
 package validator
 
 import (
-	"bytes"
-	"fmt"
-	"html/template"
+	"context"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/go-playground/validator"
 	"github.com/pkg/errors"
@@ -15,13 +15,26 @@ import (
 
 type Validate struct {
 	GPValidate           *validator.Validate
-	customTemplateMap    TemplateMap
+	customTemplates      compiledTemplates
+	localeTemplates      map[string]compiledTemplates
+	packTemplates        map[string]compiledTemplates
 	inclusionValidations map[string][]interface{}
+	ruleSets             map[string][]Rule
+	structFuncs          map[reflect.Type][]StructFunc
+	contextFuncs         map[string]ContextFunc
+	labels               LabelMap
+	normalizers          map[string]NormalizeFunc
 }
 
 type Rule struct {
 	// Field mean field name of struct, it can be nested.
 	// For example "Address.City".
+	//
+	// It can also contain slice, array and map element paths:
+	// "Items[2].Qty" mean the element of index 2,
+	// "Items[*].SKU" mean every element of Items,
+	// "Attrs[color]" mean the value of key "color".
+	// Each element is validated with its concrete path, like "Items[3].SKU".
 	Field string
 	// This tag contains tag and param, use "," to separate multiple tags.
 	// For example "required,lte=20".
@@ -29,6 +42,28 @@ type Rule struct {
 	Code    string
 	Message string
 	Err     error
+
+	// When is optional, the Rule is applied only when it returns true.
+	// data is the data passed to DoRules.
+	When func(data interface{}) bool
+	// If is optional, the Rule is applied only when all conditions hold.
+	If []Condition
+
+	// Normalize is optional, it is the normalizers applied to the string value of the field
+	// before its validation tags, use "," to separate multiple normalizers.
+	// For example "trim,halfwidth" makes "　１２３-４５６７ " pass "zipcode_jp".
+	// See RegisterNormalizer for the builtin normalizers, and Normalize to write the normalized values back.
+	Normalize string
+
+	// Redact hides the rejected value, Error.Value will be RedactedValue.
+	// Use it for secrets like passwords, so they never appear in messages or logs.
+	Redact bool
+
+	// Func is optional, if it is set, the Rule is a struct level rule,
+	// Field and Tag are ignored, and the Errors returned by Func are merged into the result,
+	// Code, Message and Err of the Rule are used for the Errors that don't have them.
+	// Func gets the struct value even if DoRules gets a pointer to struct, like RegisterStructValidation.
+	Func StructFunc
 }
 
 type Error struct {
@@ -38,26 +73,21 @@ type Error struct {
 	Code    string
 	Message string
 	Err     error
+
+	// Label is the human name of the field, see RegisterLabelMap.
+	Label string
+	// Value is the rejected value, it is nil if the value is absent,
+	// and it is RedactedValue if Rule.Redact is true.
+	Value interface{}
 }
 
+// RedactedValue is the Error.Value of the Rule that Redact is true.
+const RedactedValue = "[REDACTED]"
+
 type Errors []Error
 
 type MapError map[string][]string
 
-type TemplateMap map[string]string
-
-var defaultTemplateMap = TemplateMap{
-	"required":     "can not be blank",
-	"lte":          "is too long, maximum length is {{.Param}}",
-	"gte":          "is too short, minimum length is {{.Param}}",
-	"max":          "is too large, maximum is {{.Param}}",
-	"min":          "is too small, minimum is {{.Param}}",
-	"zipcode_jp":   "invalid zipcode format, format is 123-1234",
-	"inclusion":    "invalid {{.Param}} value",
-	"simple_email": "invalid email format",
-	"default":      "validation failed with {{ if eq .Param \"\" }}{{.Tag}}{{ else }}{{.Tag}}={{.Param}}{{ end }}",
-}
-
 func New() *Validate {
 	gpValidate := validator.New()
 
@@ -66,7 +96,21 @@ func New() *Validate {
 		panic(errors.Wrap(err, "register validation inclusion failed"))
 	}
 
-	validate := Validate{GPValidate: gpValidate, inclusionValidations: inclusionValidations}
+	validate := Validate{
+		GPValidate:           gpValidate,
+		inclusionValidations: inclusionValidations,
+		ruleSets:             map[string][]Rule{},
+		structFuncs:          map[reflect.Type][]StructFunc{},
+		contextFuncs:         map[string]ContextFunc{},
+		localeTemplates:      map[string]compiledTemplates{},
+		packTemplates:        map[string]compiledTemplates{},
+		labels:               LabelMap{},
+		normalizers:          map[string]NormalizeFunc{},
+	}
+
+	for name, fn := range builtinNormalizers {
+		validate.normalizers[name] = fn
+	}
 
 	if err := validate.RegisterRegexpValidation("zipcode_jp", `^\d{3}-\d{4}$`); err != nil {
 		panic(errors.Wrap(err, "register regexp validation zipcode_jp failed"))
@@ -120,38 +164,6 @@ func (v *Validate) RegisterInclusionValidationParam(param string, validSlice int
 	return nil
 }
 
-// val should be a struct value.
-// If found the tagName of val, then use the tag value replace name.
-//
-// It return invalid value if has some errors.
-// It return nil pointer value if try to get value from nil.
-func fieldByNameNested(val reflect.Value, name string, tagName string) (reflect.Value, string) {
-	names := []string{}
-
-	for _, n := range strings.Split(name, ".") {
-		if val.Kind() == reflect.Invalid {
-			return val, ""
-		}
-		if val.Kind() == reflect.Ptr {
-			if val.IsNil() {
-				return val, ""
-			}
-			val = val.Elem()
-		}
-
-		tag := getTagValue(val, n, tagName)
-		if tag == "" {
-			names = append(names, n)
-		} else {
-			names = append(names, tag)
-		}
-
-		val = val.FieldByName(n)
-	}
-
-	return val, strings.Join(names, ".")
-}
-
 func (ves Errors) Error() string {
 	if len(ves) == 0 {
 		return ""
@@ -159,19 +171,7 @@ func (ves Errors) Error() string {
 
 	errStrs := []string{}
 	for _, ve := range ves {
-		if ve.Param == "" {
-			if ve.Message == "" {
-				errStrs = append(errStrs, fmt.Sprintf("%v of %v", ve.Tag, ve.Field))
-			} else {
-				errStrs = append(errStrs, fmt.Sprintf("%v of %v: %v", ve.Tag, ve.Field, ve.Message))
-			}
-		} else {
-			if ve.Message == "" {
-				errStrs = append(errStrs, fmt.Sprintf("%v=%v of %v", ve.Tag, ve.Param, ve.Field))
-			} else {
-				errStrs = append(errStrs, fmt.Sprintf("%v=%v of %v: %v", ve.Tag, ve.Param, ve.Field, ve.Message))
-			}
-		}
+		errStrs = append(errStrs, ve.Error())
 	}
 
 	return "validation failed: " + strings.Join(errStrs, "; ")
@@ -186,29 +186,27 @@ func fmtStringArray(strs []string) string {
 	return `["` + strings.Join(strs, `", "`) + `"]`
 }
 
+// Error returns the error string like `Age:["is too small"] Name:["can not be blank"]`,
+// the fields are sorted.
 func (vem MapError) Error() string {
-	errStr := ""
-
-	for field, messages := range vem {
-		errStr = errStr + field + ":" + fmtStringArray(messages) + " "
-	}
-
-	if errStr != "" {
-		// Remove last " " char.
-		errStr = errStr[:len(errStr)-1]
-	}
-
-	return errStr
+	return vem.Ordered().Error()
 }
 
-// data should be a struct or a pointer to struct.
+// data should be a struct or a pointer to struct,
+// it can also be a map or a slice, like map[string]interface{} decoded from JSON,
+// then Rule.Field like "address.city" gets the value of key "city" of the map of key "address".
+//
+// A missing key of the map, a nil pointer, or a path through them means the value is absent,
+// it is validated as nil by the presence tags only, like "required" and "required_with",
+// so "required" fails, the other tags like "lte" or "email" are skipped without "omitempty",
+// and the other rules still run.
 //
 // if return (nil, nil), it mean no validation error.
 //
 // If it return (nil, error), you must to solve it. Possible errors:
-// * Invalid Rule.Tag
-// * Invalid Rule.Field
-// * data is not a struct or a pointer to struct
+// * Invalid Rule.Tag, it is *RuleError matches ErrInvalidTag by errors.Is
+// * Invalid Rule.Field, it is *RuleError matches ErrInvalidRuleField by errors.Is
+// * data is not a struct, a map, a slice or a pointer to them, it matches ErrInvalidTarget by errors.Is
 //
 // Some custom tags:
 // * zipcode_jp
@@ -245,55 +243,80 @@ func isCrossField(fieldName string) bool {
 	return isInStringArray(fieldName, crossFields)
 }
 
-func (v *Validate) DoRulesWithTagName(data interface{}, rules []Rule, tagName string) (verrs Errors, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			verrs = nil
-			err = errors.New(fmt.Sprint(r))
+func (v *Validate) DoRulesWithTagName(data interface{}, rules []Rule, tagName string) (Errors, error) {
+	return v.DoRulesContextWithTagName(context.Background(), data, rules, tagName)
+}
+
+// DoRulesContext is same as DoRules, ctx is passed to the validations registered by RegisterContextValidation.
+//
+// If ctx is done, it stops the remaining rules and returns (nil, ctx.Err()).
+// If a context validation returns error, it returns (nil, error),
+// so infrastructure errors are never mixed with the validation Errors.
+func (v *Validate) DoRulesContext(ctx context.Context, data interface{}, rules []Rule) (Errors, error) {
+	return v.DoRulesContextWithTagName(ctx, data, rules, "")
+}
+
+func (v *Validate) DoRulesContextWithTagName(ctx context.Context, data interface{}, rules []Rule, tagName string) (Errors, error) {
+	val, err := dataValue(data)
+	if err != nil {
+		return nil, err
+	}
+
+	crs := make([]compiledRule, 0, len(rules))
+	for i, rule := range rules {
+		cr, err := v.compileRule(rule)
+		if err != nil {
+			return nil, ruleError(err, i, rule)
 		}
-	}()
+		crs = append(crs, cr)
+	}
+
+	return v.runRules(ctx, data, val, crs, tagName)
+}
 
+func dataValue(data interface{}) (reflect.Value, error) {
 	val := reflect.ValueOf(data)
 	if val.Kind() == reflect.Ptr && !val.IsNil() {
 		val = val.Elem()
 	}
-	if val.Kind() != reflect.Struct {
-		return nil, errors.New("data should be a struct or a pointer to struct")
+
+	switch val.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return val, nil
 	}
 
-	verrs = Errors{}
+	return val, invalidTargetError("data should be a struct, a map, a slice or a pointer to them")
+}
 
-	for _, rule := range rules {
-		field, fieldName := fieldByNameNested(val, rule.Field, tagName)
-		if (field.Kind() == reflect.Invalid) || (field.Kind() == reflect.Ptr && field.IsNil()) {
-			return nil, errors.New(fmt.Sprintf("get value from %v field failed", rule.Field))
+func (v *Validate) runRules(ctx context.Context, data interface{}, val reflect.Value, crs []compiledRule, tagName string) (Errors, error) {
+	verrs := Errors{}
+	fields := []fieldValue{}
+
+	for i := range crs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-		fieldVal := field.Interface()
-
-		varTags := []string{}
-		for _, tag := range splitTag(rule.Tag) {
-			tagBefore := getTagBefore(tag)
-			if isCrossField(tagBefore) {
-				otherField, _ := fieldByNameNested(val, getTagAfter(tag), "")
-				if (otherField.Kind() == reflect.Invalid) || (otherField.Kind() == reflect.Ptr && otherField.IsNil()) {
-					return nil, errors.New(fmt.Sprintf("get value from %v field failed", rule.Field))
-				}
-				otherFieldVal := otherField.Interface()
 
-				verrs, err = appendErrors(v.GPValidate.VarWithValue(fieldVal, otherFieldVal, tagBefore), verrs, fieldName, rule.Code, rule.Message, rule.Err)
-				if err != nil {
-					return nil, err
-				}
-			} else {
-				varTags = append(varTags, tag)
-			}
+		cr := &crs[i]
+
+		var err error
+		verrs, fields, err = v.runRule(ctx, data, val, cr, tagName, verrs, fields)
+		if err != nil {
+			return nil, ruleError(err, i, cr.rule)
 		}
+	}
 
-		if len(varTags) > 0 {
-			verrs, err = appendErrors(v.GPValidate.Var(fieldVal, strings.Join(varTags, tagSeparator)), verrs, fieldName, rule.Code, rule.Message, rule.Err)
+	if val.Kind() == reflect.Struct {
+		for _, fn := range v.structFuncs[val.Type()] {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			fnVerrs, err := runStructFunc(fn, val, tagName)
 			if err != nil {
 				return nil, err
 			}
+			verrs = append(verrs, fnVerrs...)
 		}
 	}
 
@@ -304,260 +327,494 @@ func (v *Validate) DoRulesWithTagName(data interface{}, rules []Rule, tagName st
 	return verrs, nil
 }
 
-func setVErrsToStruct(verrs Errors, toStruct interface{}) {
-	messageMap := map[string][]string{}
-	errMap := map[string][]error{}
-	for _, verr := range verrs {
-		messageMap[verr.Field] = append(messageMap[verr.Field], verr.Message)
-		errMap[verr.Field] = append(errMap[verr.Field], verr.Err)
+// runRule runs the rule cr and appends its errors to verrs,
+// fields is reused to resolve the values of the rule.
+// The panic is returned as RuleError, it is ErrValidatorPanic if it is from the user code,
+// or ErrInvalidTag if it is from go-playground/validator, like the tag is not registered, see panicRuleError.
+func (v *Validate) runRule(ctx context.Context, data interface{}, val reflect.Value, cr *compiledRule, tagName string, verrs Errors, fields []fieldValue) (_ Errors, _ []fieldValue, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicRuleError(r)
+		}
+	}()
+
+	ok, err := v.ruleApplies(data, val, cr)
+	if err != nil {
+		return nil, fields, err
+	}
+	if !ok {
+		return verrs, fields, nil
 	}
 
-	v := reflect.ValueOf(toStruct).Elem()
-	if v.IsNil() {
-		v.Set(reflect.New(v.Type().Elem()))
+	if cr.rule.Func != nil {
+		return append(verrs, structErrors(val, callStructFunc("Func", cr.rule.Func, val.Interface()), tagName, &cr.rule)...), fields, nil
 	}
-	vv := v.Elem()
 
-	for field, messages := range messageMap {
-		vvf := vv.FieldByName(field)
-		messagesV := reflect.ValueOf(messages)
-		if vvf.Type().String() == messagesV.Type().String() {
-			vvf.Set(reflect.ValueOf(messages))
-		}
+	fields, err = cr.path.appendResolved(fields[:0], val, tagName)
+	if err != nil {
+		return nil, fields, &RuleError{Kind: ErrInvalidRuleField, Err: err}
 	}
-	for field, errs := range errMap {
-		vvf := vv.FieldByName(field)
-		errsV := reflect.ValueOf(errs)
-		if vvf.Type().String() == errsV.Type().String() {
-			vvf.Set(reflect.ValueOf(errs))
+
+	for _, field := range fields {
+		verrs, err = v.validateField(ctx, val, field, cr, verrs)
+		if err != nil {
+			return nil, fields, err
 		}
 	}
-}
 
-func setToStructToNil(toStruct interface{}) {
-	v := reflect.ValueOf(toStruct)
-	v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	return verrs, fields, nil
 }
 
-func checkToStruct(toStruct interface{}) {
-	v := reflect.ValueOf(toStruct)
-	if v.Kind() != reflect.Ptr {
-		panic(errors.New("toStruct must be pointer to pointer to struct"))
+func (v *Validate) validateField(ctx context.Context, val reflect.Value, field fieldValue, cr *compiledRule, verrs Errors) (Errors, error) {
+	rule := cr.rule
+	verrsLen := len(verrs)
+
+	// The absent value is validated as nil by the presence tags only, so required fails,
+	// and the other tags are skipped, there is nothing to check, like lte or email.
+	// The string value is validated after the normalizers of the rule.
+	fieldVal := field.normalize(cr.normalizers, false)
+
+	baseErr := Error{
+		Field:   field.name,
+		Label:   field.label,
+		Value:   errorValue(fieldVal, rule.Redact),
+		Code:    rule.Code,
+		Message: rule.Message,
+		Err:     rule.Err,
 	}
-	vv := v.Elem()
-	if vv.Kind() != reflect.Ptr {
-		panic(errors.New("toStruct must be pointer to pointer to struct"))
+	if label := v.labels[rule.Field]; label != "" {
+		baseErr.Label = label
 	}
-}
 
-// toStruct must be pointer to pointer to struct,
-// and fields must contains all rule.Field and type must be []string.
-// If no validation errors, then set toStruct to nil.
-func (v *Validate) DoRulesToStructAndSetNil(data interface{}, rules []Rule, toStruct interface{}) {
-	checkToStruct(toStruct)
+	var err error
+	for _, crossTag := range cr.crossTags {
+		if fieldVal == nil {
+			break
+		}
 
-	verrs, err := v.DoRules(data, rules)
-	if err != nil {
-		panic(err)
+		otherField, err := crossTag.path.resolveOne(val, "")
+		if err != nil {
+			return nil, &RuleError{Tag: crossTag.tag + tagKeySeparator + crossTag.path.path, Kind: ErrInvalidRuleField, Err: err}
+		}
+		otherFieldVal := otherField.interfaceOrNil()
+		if otherFieldVal == nil {
+			continue
+		}
+
+		verrs, err = appendErrors(v.gpVarWithValue(fieldVal, otherFieldVal, crossTag.tag), verrs, baseErr)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if len(verrs) == 0 {
-		setToStructToNil(toStruct)
-		return
+	varTag := cr.varTag
+	if fieldVal == nil {
+		varTag = cr.presenceTag
+	}
+	if varTag != "" {
+		verrs, err = appendErrors(v.gpVar(fieldVal, varTag), verrs, baseErr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The context validations may be expensive, like querying database,
+	// so they run only when the other tags pass.
+	// The pointers are dereferenced, like the other tags.
+	value := errorValue(fieldVal, false)
+	if len(verrs) > verrsLen || (cr.omitEmpty && isEmpty(value)) {
+		return verrs, nil
 	}
 
-	setVErrsToStruct(verrs, toStruct)
+	for _, ct := range cr.contextTags {
+		ok, err := callContextFunc(ctx, ct, value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "context validation %v of %v failed", ct.tag, field.name)
+		}
+		if !ok {
+			verr := baseErr
+			verr.Tag = ct.tag
+			verr.Param = ct.param
+			verrs = append(verrs, verr)
+		}
+	}
+
+	return verrs, nil
 }
 
-// TODO toStruct can be pointer to struct.
-// toStruct must be pointer to pointer to struct,
-// and fields must contains all rule.Field and type must be []string.
-func (v *Validate) DoRulesToStruct(data interface{}, rules []Rule, toStruct interface{}) {
-	checkToStruct(toStruct)
+// ToStructOptions is the options of DoRulesToStructWithOptions and VErrorsToStruct.
+type ToStructOptions struct {
+	// TagName is the tagName of DoRulesWithTagName,
+	// the fields of toStruct are looked up by it too.
+	TagName string
+	// RenderMessages fills the []string fields with the messages rendered by the templates,
+	// instead of Rule.Message, see VErrorsToMapLocale.
+	RenderMessages bool
+	// Locale is the locale of the templates, it is used if RenderMessages is true.
+	Locale string
+	// SetNil sets toStruct to nil if no validation errors,
+	// or to the zero value if toStruct is a pointer to struct.
+	SetNil bool
+}
 
-	verrs, err := v.DoRules(data, rules)
+// VErrorsToStruct sets verrs to toStruct, toStruct can be a pointer to struct or a pointer to pointer to struct.
+//
+// Field of the Error is looked up in toStruct like Rule.Field,
+// the []string field gets the messages, and the []error field gets Err of the rules.
+// The nested paths fill the nested error structs, like "Address.City" fills City of the Address field,
+// and the indexed paths fill the slice of error structs, like "Items[2].SKU" fills SKU of the 3rd element,
+// the nil pointers are allocated and the slices are grown as needed.
+// The promoted fields of the embedded error structs are filled too, like Name of an embedded CommonError.
+// The errors that can't be found in toStruct are ignored.
+// If toStruct is a pointer to nil pointer, it is allocated even if no validation errors, unless options.SetNil.
+//
+// If toStruct is invalid or render message failed, it will return error.
+func (v *Validate) VErrorsToStruct(verrs Errors, toStruct interface{}, options ToStructOptions) error {
+	target, err := toStructValue(toStruct)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	setVErrsToStruct(verrs, toStruct)
-}
-
-func verrsToProtoError(verrs Errors) (protoErr *proto.Error) {
+	if len(verrs) == 0 && options.SetNil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	if target.Kind() == reflect.Ptr && target.IsNil() {
+		target.Set(reflect.New(target.Type().Elem()))
+	}
 	if len(verrs) == 0 {
 		return nil
 	}
 
-	protoErr = new(proto.Error)
-	for _, verr := range verrs {
-		protoErr.FieldViolations = append(
-			protoErr.FieldViolations,
-			&proto.ValidationError_FieldViolation{
-				Field: verr.Field,
-				Code:  verr.Code,
-				Param: verr.Param,
-				Msg:   verr.Message,
-			},
-		)
+	messages := make([]string, len(verrs))
+	chain := v.templatesChain(options.Locale)
+	for i, verr := range verrs {
+		if !options.RenderMessages {
+			messages[i] = verr.Message
+			continue
+		}
+
+		messages[i], err = executeTemplate(lookupTemplateInChain(verr.Tag, chain), newTemplateValues(verr))
+		if err != nil {
+			return errors.Wrap(err, "executeTemplate failed")
+		}
 	}
 
-	return protoErr
+	setVErrsToStruct(verrs, messages, target, options.TagName)
+
+	return nil
 }
 
-// If no any error, return nil.
-func (v *Validate) DoRulesToProtoError(data interface{}, rules []Rule) *proto.Error {
-	verrs, err := v.DoRules(data, rules)
-	if err != nil {
-		panic(err)
+// toStructValue returns the settable value of toStruct, it is a pointer to struct or a struct.
+func toStructValue(toStruct interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(toStruct)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return reflect.Value{}, invalidTargetError("toStruct must be pointer to struct or pointer to pointer to struct")
+	}
+
+	vv := v.Elem()
+	if vv.Kind() == reflect.Struct || (vv.Kind() == reflect.Ptr && vv.Type().Elem().Kind() == reflect.Struct) {
+		return vv, nil
 	}
 
-	return verrsToProtoError(verrs)
+	return reflect.Value{}, invalidTargetError("toStruct must be pointer to struct or pointer to pointer to struct")
 }
 
-func appendErrors(err error, verrs Errors, fieldName string, code string, message string, ruleErr error) (Errors, error) {
-	if _, ok := err.(*validator.InvalidValidationError); ok {
-		return nil, err
-	}
+var (
+	stringsType = reflect.TypeOf([]string(nil))
+	errorsType  = reflect.TypeOf([]error(nil))
+)
 
-	if validationErrors, ok := err.(validator.ValidationErrors); ok {
-		for _, validationErr := range validationErrors {
-			verrs = append(verrs, Error{
-				Field:   fieldName,
-				Tag:     validationErr.Tag(),
-				Param:   validationErr.Param(),
-				Code:    code,
-				Message: message,
-				Err:     ruleErr,
-			})
+func setVErrsToStruct(verrs Errors, messages []string, target reflect.Value, tagName string) {
+	fields := []string{}
+	messageMap := map[string][]string{}
+	errMap := map[string][]error{}
+	for i, verr := range verrs {
+		if _, ok := messageMap[verr.Field]; !ok {
+			fields = append(fields, verr.Field)
 		}
+		messageMap[verr.Field] = append(messageMap[verr.Field], messages[i])
+		errMap[verr.Field] = append(errMap[verr.Field], verr.Err)
 	}
 
-	return verrs, nil
+	for _, field := range fields {
+		segments, err := parsePath(field)
+		if err != nil {
+			continue
+		}
+
+		fieldVal, ok := errorFieldValue(target, segments, tagName)
+		if !ok {
+			continue
+		}
+
+		switch fieldVal.Type() {
+		case stringsType:
+			fieldVal.Set(reflect.ValueOf(messageMap[field]))
+		case errorsType:
+			fieldVal.Set(reflect.ValueOf(errMap[field]))
+		}
+	}
 }
 
-// It's a proxy function for validate.Var from github.com/go-playground/validator.
-// But it return bool type.
-//
-// If has some invalid input, it will return false.
-func (v *Validate) IsVar(field interface{}, tag string) bool {
-	err := v.GPValidate.Var(field, tag)
+// errorFieldValue gets the settable field of the path in the error struct val,
+// the nil pointers are allocated and the slices are grown on the way.
+func errorFieldValue(val reflect.Value, segments []pathSegment, tagName string) (reflect.Value, bool) {
+	for _, segment := range segments {
+		if !allocPointers(&val) {
+			return reflect.Value{}, false
+		}
 
-	if _, ok := err.(*validator.InvalidValidationError); ok {
-		return false
+		switch val.Kind() {
+		case reflect.Struct:
+			if segment.bracket {
+				return reflect.Value{}, false
+			}
+			index, _, ok := structFieldByName(val.Type(), segment.name, tagName)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			for j, i := range index {
+				if j > 0 && !allocPointers(&val) {
+					return reflect.Value{}, false
+				}
+				val = val.Field(i)
+			}
+		case reflect.Slice:
+			i, err := strconv.Atoi(segment.name)
+			if !segment.bracket || err != nil || i < 0 || !val.CanSet() {
+				return reflect.Value{}, false
+			}
+			if i >= val.Len() {
+				grown := reflect.MakeSlice(val.Type(), i+1, i+1)
+				reflect.Copy(grown, val)
+				val.Set(grown)
+			}
+			val = val.Index(i)
+		default:
+			return reflect.Value{}, false
+		}
 	}
 
-	if _, ok := err.(validator.ValidationErrors); ok {
-		return false
+	return val, val.CanSet()
+}
+
+// allocPointers dereferences the pointers of val, the nil pointers are allocated,
+// it returns false if a nil pointer can't be set, like an unexported embedded pointer.
+func allocPointers(val *reflect.Value) bool {
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			if !val.CanSet() {
+				return false
+			}
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		*val = val.Elem()
 	}
 
 	return true
 }
 
-type templateValues struct {
-	Param string
-	Tag   string
+func checkToStruct(toStruct interface{}) {
+	if _, err := toStructValue(toStruct); err != nil {
+		panic(err)
+	}
 }
 
-func getTemplate(tag string, customTemplateMap TemplateMap) string {
-	if message := customTemplateMap[tag]; message != "" {
-		return message
+// toStruct must be pointer to struct or pointer to pointer to struct, see VErrorsToStruct.
+// If no validation errors, then set toStruct to nil,
+// or to the zero value if toStruct is pointer to struct.
+//
+// It panics if the rules, data or toStruct are invalid, use TryDoRulesToStructAndSetNil to get the error.
+func (v *Validate) DoRulesToStructAndSetNil(data interface{}, rules []Rule, toStruct interface{}) {
+	checkToStruct(toStruct)
+
+	if err := v.TryDoRulesToStructAndSetNil(data, rules, toStruct); err != nil {
+		panic(err)
 	}
+}
+
+// TryDoRulesToStructAndSetNil is same as DoRulesToStructAndSetNil, but returns the error instead of panic.
+// The error matches ErrInvalidRuleField, ErrInvalidTag or ErrInvalidTarget by errors.Is,
+// and it is *RuleError with the offending rule for the former two.
+func (v *Validate) TryDoRulesToStructAndSetNil(data interface{}, rules []Rule, toStruct interface{}) error {
+	_, err := v.DoRulesToStructWithOptions(data, rules, toStruct, ToStructOptions{SetNil: true})
+	return err
+}
+
+// toStruct must be pointer to struct or pointer to pointer to struct, see VErrorsToStruct.
+//
+// It panics if the rules, data or toStruct are invalid, use TryDoRulesToStruct to get the error.
+func (v *Validate) DoRulesToStruct(data interface{}, rules []Rule, toStruct interface{}) {
+	checkToStruct(toStruct)
 
-	if message := defaultTemplateMap[tag]; message != "" {
-		return message
+	if err := v.TryDoRulesToStruct(data, rules, toStruct); err != nil {
+		panic(err)
 	}
+}
 
-	return defaultTemplateMap["default"]
+// TryDoRulesToStruct is same as DoRulesToStruct, but returns the error instead of panic,
+// see TryDoRulesToStructAndSetNil.
+func (v *Validate) TryDoRulesToStruct(data interface{}, rules []Rule, toStruct interface{}) error {
+	_, err := v.DoRulesToStructWithOptions(data, rules, toStruct, ToStructOptions{})
+	return err
 }
 
-func parseTemplate(tplValues templateValues, templateStr string) (string, error) {
-	tl, err := template.New("validate").Parse(templateStr)
+// DoRulesToStructWithOptions is same as DoRulesToStruct, but it returns the Errors and the error instead of panic,
+// see ToStructOptions and VErrorsToStruct.
+func (v *Validate) DoRulesToStructWithOptions(data interface{}, rules []Rule, toStruct interface{}, options ToStructOptions) (Errors, error) {
+	if _, err := toStructValue(toStruct); err != nil {
+		return nil, err
+	}
+
+	verrs, err := v.DoRulesWithTagName(data, rules, options.TagName)
 	if err != nil {
-		return "", errors.Wrap(err, "template parse failed")
+		return nil, err
 	}
 
-	message := bytes.Buffer{}
-	if err := tl.Execute(&message, tplValues); err != nil {
-		return "", errors.Wrap(err, "template execute failed")
+	if err := v.VErrorsToStruct(verrs, toStruct, options); err != nil {
+		return nil, err
 	}
 
-	return message.String(), nil
+	return verrs, nil
 }
 
-// templateMap is a map that mean [tag]template,
-// templateMap will be parsed by go template,
-// you can use ".Tag" and ".Param" variable in the template.
-//
-// For example, validation tag is "max=100", then tag is "max", param is "100",
-// if template is "is too large, maximum is {{.Param}}",
-// then it will be parsed to "is too large, maximum is 100".
+// VErrorsToProtoError is same as VErrorsToProtoErrorLocale, but uses the templates without locale.
+func (v *Validate) VErrorsToProtoError(verrs Errors) (*proto.Error, error) {
+	return v.VErrorsToProtoErrorLocale(verrs, "")
+}
+
+// VErrorsToProtoErrorLocale converts verrs to proto.Error, it returns nil if verrs is empty.
 //
-// If templateMap is nil, it will use defaultTemplateMap to parse.
-// If not found the tag in the templateMap, it will use defaultTemplateMap to parse for this tag,
-// it mean templateMap will merge to defaultTemplateMap,
-// so you don't worry about missing some tags of the templateMap.
+// DefaultViewMsg of each FieldViolation is rendered by the templates of locale, see VErrorsToMapLocale,
+// Code is the Code of the Rule, or the tag if it is empty, and Msg is the Message of the Rule.
 //
-// If parse template failed, it will return error.
-func VErrorsToMap(verrs Errors, templateMap TemplateMap) (MapError, error) {
-	verrMap := MapError{}
+// Msg of the proto.Error is the summary of all errors, same as verrs.Error(),
+// and DefaultViewMsg is the DefaultViewMsg of the first FieldViolation.
+func (v *Validate) VErrorsToProtoErrorLocale(verrs Errors, locale string) (*proto.Error, error) {
+	return verrsToProtoError(verrs, v.templatesChain(locale))
+}
+
+// verrsToProtoError converts verrs to proto.Error, DefaultViewMsg is rendered by the templates of chain.
+func verrsToProtoError(verrs Errors, chain []compiledTemplates) (*proto.Error, error) {
+	if len(verrs) == 0 {
+		return nil, nil
+	}
+
+	protoErr := &proto.Error{Msg: verrs.Error()}
 	for _, verr := range verrs {
-		vMessage, err := parseTemplate(templateValues{Param: verr.Param, Tag: verr.Tag}, getTemplate(verr.Tag, templateMap))
+		viewMsg, err := executeTemplate(lookupTemplateInChain(verr.Tag, chain), newTemplateValues(verr))
 		if err != nil {
-			return nil, errors.Wrap(err, "parseTemplate failed")
+			return nil, errors.Wrap(err, "executeTemplate failed")
 		}
-		verrMap[verr.Field] = append(verrMap[verr.Field], vMessage)
+
+		code := verr.Code
+		if code == "" {
+			code = verr.Tag
+		}
+
+		protoErr.FieldViolations = append(
+			protoErr.FieldViolations,
+			&proto.ValidationError_FieldViolation{
+				Field:          verr.Field,
+				Code:           code,
+				Param:          verr.Param,
+				Msg:            verr.Message,
+				DefaultViewMsg: viewMsg,
+			},
+		)
 	}
-	return verrMap, nil
+	protoErr.DefaultViewMsg = protoErr.FieldViolations[0].DefaultViewMsg
+
+	return protoErr, nil
 }
 
-func (v *Validate) RegisterTemplateMap(templateMap TemplateMap) error {
-	if err := checkTemplateMap(templateMap); err != nil {
-		return err
-	}
+// If no any error, return nil.
+//
+// It panics if the rules or data are invalid, use TryDoRulesToProtoError to get the error.
+func (v *Validate) DoRulesToProtoError(data interface{}, rules []Rule) *proto.Error {
+	return v.DoRulesToProtoErrorLocale(data, rules, "")
+}
 
-	v.customTemplateMap = templateMap
+// TryDoRulesToProtoError is same as DoRulesToProtoError, but returns the error instead of panic,
+// see TryDoRulesToStructAndSetNil.
+func (v *Validate) TryDoRulesToProtoError(data interface{}, rules []Rule) (*proto.Error, error) {
+	return v.TryDoRulesToProtoErrorLocale(data, rules, "")
+}
 
-	return nil
+// DoRulesToProtoErrorLocale is same as DoRulesToProtoError,
+// but DefaultViewMsg is rendered by the templates of locale, see VErrorsToProtoErrorLocale.
+func (v *Validate) DoRulesToProtoErrorLocale(data interface{}, rules []Rule, locale string) *proto.Error {
+	protoErr, err := v.TryDoRulesToProtoErrorLocale(data, rules, locale)
+	if err != nil {
+		panic(err)
+	}
+
+	return protoErr
 }
 
-func checkTemplateMap(templateMap TemplateMap) error {
-	tplValues := templateValues{
-		Param: "check param",
-		Tag:   "check tag",
+// TryDoRulesToProtoErrorLocale is same as DoRulesToProtoErrorLocale, but returns the error instead of panic.
+func (v *Validate) TryDoRulesToProtoErrorLocale(data interface{}, rules []Rule, locale string) (*proto.Error, error) {
+	verrs, err := v.DoRules(data, rules)
+	if err != nil {
+		return nil, err
 	}
 
-	for tag, tpl := range templateMap {
-		if tag == "" {
-			return errors.New("tag of the templateMap can not be empty")
-		}
+	return v.VErrorsToProtoErrorLocale(verrs, locale)
+}
 
-		_, err := parseTemplate(tplValues, tpl)
-		if err != nil {
-			return errors.Wrap(err, "tpl of the templateMap invalid")
+// appendErrors appends an Error for each validation error of err,
+// baseErr contains the other fields except Tag and Param.
+func appendErrors(err error, verrs Errors, baseErr Error) (Errors, error) {
+	if _, ok := err.(*validator.InvalidValidationError); ok {
+		return nil, &RuleError{Kind: ErrInvalidTag, Err: err}
+	}
+
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		for _, validationErr := range validationErrors {
+			verr := baseErr
+			verr.Tag = validationErr.Tag()
+			verr.Param = validationErr.Param()
+			verrs = append(verrs, verr)
 		}
 	}
 
-	return nil
+	return verrs, nil
 }
 
-// Same as DoRules, run DoRules and VErrorsToMap with custom template.
-// You can use RegisterTemplateMap func to register custom template.
-func (v *Validate) DoRulesAndToMapError(data interface{}, rules []Rule) (MapError, error) {
-	verrs, err := v.DoRules(data, rules)
-	if err != nil {
-		return nil, err
+// errorValue returns the value for Error.Value, the pointer is dereferenced.
+func errorValue(value interface{}, redact bool) interface{} {
+	if redact {
+		return RedactedValue
 	}
 
-	return VErrorsToMap(verrs, v.customTemplateMap)
+	val := reflect.ValueOf(value)
+	for val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+	if !val.IsValid() || (val.Kind() == reflect.Ptr && val.IsNil()) {
+		return nil
+	}
+
+	return val.Interface()
 }
 
-func (v *Validate) DoRulesAndToMapErrorWithTagName(data interface{}, rules []Rule, tagName string) (MapError, error) {
-	verrs, err := v.DoRulesWithTagName(data, rules, tagName)
-	if err != nil {
-		return nil, err
+// It's a proxy function for validate.Var from github.com/go-playground/validator.
+// But it return bool type.
+//
+// If has some invalid input, it will return false.
+func (v *Validate) IsVar(field interface{}, tag string) bool {
+	err := v.GPValidate.Var(field, tag)
+
+	if _, ok := err.(*validator.InvalidValidationError); ok {
+		return false
 	}
 
-	return VErrorsToMap(verrs, v.customTemplateMap)
+	if _, ok := err.(validator.ValidationErrors); ok {
+		return false
+	}
+
+	return true
 }
 
 // RegisterValidation adds a validation with the given tag.
@@ -570,27 +827,59 @@ func (v *Validate) DoRulesAndToMapErrorWithTagName(data interface{}, rules []Rul
 // this is not good, because we hope only import this package.
 // To find a better way.
 func (v *Validate) RegisterValidation(tag string, fn func(validator.FieldLevel) bool) error {
-	return v.GPValidate.RegisterValidation(tag, fn)
+	return v.GPValidate.RegisterValidation(tag, guardValidation(tag, fn))
 }
 
-// RegisterRegexpValidation adds a regexp validation with the given tag and regexpString
+// RegisterRegexpValidation adds a regexp validation with the given tag and regexpString,
+// regexpString is compiled once here, if it is invalid, it will return error.
 //
 // NOTES:
 // - if the key already exists, the previous validation function will be replaced.
 // - this method is not thread-safe it is intended that these all be registered prior to any validation
 func (v *Validate) RegisterRegexpValidation(tag string, regexpString string) error {
-	return v.GPValidate.RegisterValidation(tag, generateRegexpValidation(regexpString))
+	return v.RegisterRegexpValidationWithOptions(tag, regexpString, RegexpOptions{})
+}
+
+type RegexpOptions struct {
+	// Anchored makes the regexp match the whole value, like `^(?:regexpString)$`.
+	Anchored bool
+	// CaseInsensitive makes the regexp case-insensitive, like `(?i)regexpString`.
+	CaseInsensitive bool
+	// MaxLength is the maximum number of characters of the value,
+	// the longer value fails without matching. 0 means no limit.
+	MaxLength int
+}
+
+// RegisterRegexpValidationWithOptions is same as RegisterRegexpValidation, but with options.
+func (v *Validate) RegisterRegexpValidationWithOptions(tag string, regexpString string, options RegexpOptions) error {
+	if options.MaxLength < 0 {
+		return errors.New("MaxLength can not be negative")
+	}
+
+	if options.Anchored {
+		regexpString = `^(?:` + regexpString + `)$`
+	}
+	if options.CaseInsensitive {
+		regexpString = `(?i)` + regexpString
+	}
+
+	re, err := regexp.Compile(regexpString)
+	if err != nil {
+		return errors.Wrapf(err, "compile regexp of %v failed", tag)
+	}
+
+	return v.GPValidate.RegisterValidation(tag, generateRegexpValidation(re, options.MaxLength))
 }
 
-func generateRegexpValidation(regexpString string) func(fl validator.FieldLevel) bool {
+func generateRegexpValidation(re *regexp.Regexp, maxLength int) func(fl validator.FieldLevel) bool {
 	return func(fl validator.FieldLevel) bool {
 		val := fl.Field().String()
 
-		if !regexp.MustCompile(regexpString).MatchString(val) {
+		if maxLength > 0 && utf8.RuneCountInString(val) > maxLength {
 			return false
 		}
 
-		return true
+		return re.MatchString(val)
 	}
 }
 
@@ -89,8 +89,8 @@ func TestValidate_DoRulesWithCrossFields(t *testing.T) {
 	fatalassert.NoError(t, err)
 
 	wantVerrs := validator.Errors{
-		{Field: "Name", Tag: "eqfield"},
-		{Field: "Address", Tag: "nefield"},
+		{Field: "Name", Tag: "eqfield", Value: "name"},
+		{Field: "Address", Tag: "nefield", Value: "address"},
 	}
 
 	fatalassert.Equal(t, wantVerrs, verrs)
@@ -191,6 +191,7 @@ func TestValidate_DoRulesWithMessageCanBeReturn(t *testing.T) {
 		Field:   "Name",
 		Tag:     "required",
 		Message: "I'm a message",
+		Value:   "",
 	}
 
 	if !reflect.DeepEqual(gotVerrs[0], wantVerrs) {
@@ -221,6 +222,7 @@ func TestValidate_DoRulesWithErrCanBeReturn(t *testing.T) {
 		Tag:     "required",
 		Message: "I'm a message",
 		Err:     ruleErr,
+		Value:   "",
 	}
 
 	if !reflect.DeepEqual(gotVerrs[0], wantVerrs) {
@@ -780,10 +782,8 @@ func TestValidate_DoRulesToStructAndSetNil(t *testing.T) {
 
 func TestValidate_DoRulesToStructAndSetNil__toStructCheck1(t *testing.T) {
 	defer func() {
-		if r := recover(); r != nil {
-			if fmt.Sprint(r) != "toStruct must be pointer to pointer to struct" {
-				t.Fatal("should panic 'toStruct must be pointer to pointer to struct'")
-			}
+		if r := recover(); fmt.Sprint(r) != "toStruct must be pointer to struct or pointer to pointer to struct" {
+			t.Fatal("should panic 'toStruct must be pointer to struct or pointer to pointer to struct'")
 		}
 	}()
 
@@ -792,7 +792,7 @@ func TestValidate_DoRulesToStructAndSetNil__toStructCheck1(t *testing.T) {
 	validate := validator.New()
 
 	var infoErr infoStringsError
-	validate.DoRulesToStructAndSetNil(infoEmpty, infoRules, &infoErr)
+	validate.DoRulesToStructAndSetNil(infoEmpty, infoRules, infoErr)
 }
 
 func TestValidate_DoRulesToStructAndSetNil__toStructCheck2(t *testing.T) {
@@ -863,6 +863,142 @@ func TestValidate_DoRulesToStruct__NoValidationErrorsAndNoSetNil(t *testing.T) {
 	}
 }
 
+func TestValidate_DoRulesToStruct__NoValidationErrorsAndNilPointer(t *testing.T) {
+	infoEmpty := info{Name: "name", FirstName: "first name", Password: "password", Age: 30, Address: "address", ZipCode: "000-0000"}
+
+	validate := validator.New()
+
+	var infoErr *infoStringsError
+	validate.DoRulesToStruct(infoEmpty, infoRules, &infoErr)
+	fatalassert.Equal(t, &infoStringsError{}, infoErr)
+}
+
+func TestValidate_DoRulesToStructWithPointerToStruct(t *testing.T) {
+	validate := validator.New()
+
+	var infoErr infoStringsError
+	validate.DoRulesToStruct(info{Password: "long password"}, infoRules, &infoErr)
+
+	wantInfoErr := infoStringsError{
+		Name:      []string{"name"},
+		FirstName: []string{"first name"},
+		Age:       []string{"age"},
+		Address:   []string{"address"},
+		ZipCode:   []string{"zipcode"},
+	}
+
+	fatalassert.Equal(t, wantInfoErr, infoErr)
+
+	validate.DoRulesToStructAndSetNil(info{Name: "name", FirstName: "first name", Password: "password", Age: 30, Address: "address", ZipCode: "000-0000"}, infoRules, &infoErr)
+	fatalassert.Equal(t, infoStringsError{}, infoErr)
+}
+
+type addressError struct {
+	City []string
+}
+
+type itemError struct {
+	SKU []string `json:"sku"`
+	Qty []error  `json:"qty"`
+}
+
+type orderError struct {
+	Name    []string      `json:"name"`
+	Address *addressError `json:"address"`
+	Items   []itemError   `json:"items"`
+}
+
+type nestedOrder struct {
+	Name    string      `json:"name"`
+	Address address     `json:"address"`
+	Items   []orderItem `json:"items"`
+}
+
+func TestValidate_DoRulesToStructWithNestedErrors(t *testing.T) {
+	validate := validator.New()
+
+	rules := []validator.Rule{
+		{Field: "Name", Tag: "required", Message: "name required"},
+		{Field: "Address.City", Tag: "required", Message: "city required"},
+		{Field: "Items[*].SKU", Tag: "required", Message: "sku required"},
+		{Field: "Items[*].Qty", Tag: "min=1", Err: ErrAge},
+	}
+
+	data := nestedOrder{Items: []orderItem{{SKU: "a", Qty: 1}, {SKU: "b"}, {}}}
+
+	var orderErr *orderError
+	validate.DoRulesToStruct(data, rules, &orderErr)
+
+	fatalassert.Equal(t, &orderError{
+		Name:    []string{"name required"},
+		Address: &addressError{City: []string{"city required"}},
+		Items: []itemError{
+			{},
+			{Qty: []error{ErrAge}},
+			{SKU: []string{"sku required"}, Qty: []error{ErrAge}},
+		},
+	}, orderErr)
+
+	var renderedErr orderError
+	verrs, err := validate.DoRulesToStructWithOptions(data, rules, &renderedErr, validator.ToStructOptions{
+		TagName:        "json",
+		RenderMessages: true,
+		Locale:         "ja",
+	})
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, 5, len(verrs))
+
+	fatalassert.Equal(t, []string{"必須項目です"}, renderedErr.Name)
+	fatalassert.Equal(t, []string{"必須項目です"}, renderedErr.Address.City)
+	fatalassert.Equal(t, []string{"必須項目です"}, renderedErr.Items[2].SKU)
+
+	if _, err := validate.DoRulesToStructWithOptions(data, rules, renderedErr, validator.ToStructOptions{}); err == nil {
+		t.Fatal("should return error when toStruct is not a pointer")
+	}
+}
+
+type contactCommon struct {
+	Name string `json:"name"`
+}
+
+type contact struct {
+	contactCommon
+	Age int `json:"age"`
+}
+
+type ContactCommonError struct {
+	Name []string `json:"name"`
+}
+
+type contactError struct {
+	ContactCommonError
+	Age []string `json:"age"`
+}
+
+func TestValidate_DoRulesToStructWithEmbeddedErrors(t *testing.T) {
+	validate := validator.New()
+
+	rules := []validator.Rule{
+		{Field: "Name", Tag: "required", Message: "name"},
+		{Field: "Age", Tag: "min=1", Message: "age"},
+	}
+
+	wantContactErr := &contactError{ContactCommonError: ContactCommonError{Name: []string{"name"}}, Age: []string{"age"}}
+
+	var contactErr *contactError
+	validate.DoRulesToStruct(contact{}, rules, &contactErr)
+	fatalassert.Equal(t, wantContactErr, contactErr)
+
+	contactErr = nil
+	_, err := validate.DoRulesToStructWithOptions(contact{}, rules, &contactErr, validator.ToStructOptions{TagName: "json"})
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, wantContactErr, contactErr)
+
+	verrs, err := validate.DoRulesWithTagName(contact{}, []validator.Rule{rules[1], rules[0]}, "json")
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, []string{"name", "age"}, verrs.SortByStruct(&contact{}, "json").Fields())
+}
+
 func TestValidate_ToStructContainsErrorsType(t *testing.T) {
 	infoEmpty := info{}
 
@@ -893,22 +1029,27 @@ func TestValidate_DoRulesToProtoError(t *testing.T) {
 	protoError := validate.DoRulesToProtoError(emptyUser, userRules)
 
 	wantProtoError := &proto.Error{
+		Msg:            "validation failed: required of Name: Name required; min=20 of Age: Age < 20; required of Address.City: Address.City required",
+		DefaultViewMsg: "can not be blank",
 		FieldViolations: []*proto.ValidationError_FieldViolation{
 			{
-				Field: "Name",
-				Code:  "1-required",
-				Msg:   "Name required",
+				Field:          "Name",
+				Code:           "1-required",
+				Msg:            "Name required",
+				DefaultViewMsg: "can not be blank",
 			},
 			{
-				Field: "Age",
-				Code:  "2-min",
-				Param: "20",
-				Msg:   "Age < 20",
+				Field:          "Age",
+				Code:           "2-min",
+				Param:          "20",
+				Msg:            "Age < 20",
+				DefaultViewMsg: "is too small, minimum is 20",
 			},
 			{
-				Field: "Address.City",
-				Code:  "1-required",
-				Msg:   "Address.City required",
+				Field:          "Address.City",
+				Code:           "1-required",
+				Msg:            "Address.City required",
+				DefaultViewMsg: "can not be blank",
 			},
 		},
 	}
@@ -926,3 +1067,34 @@ func TestValidate_DoRulesToProtoError__NoError(t *testing.T) {
 		t.Fatal("protoError should be nil when no any error")
 	}
 }
+
+func TestValidate_DoRulesToProtoErrorLocale(t *testing.T) {
+	validate := validator.New()
+	fatalassert.NoError(t, validate.RegisterTemplateMap(validator.TemplateMap{"min": "{{.Label}} is less than {{.Param}}"}))
+
+	rules := []validator.Rule{
+		{Field: "Name", Tag: "required"},
+		{Field: "Age", Tag: "min=20"},
+	}
+
+	protoError := validate.DoRulesToProtoErrorLocale(user{Name: "name"}, rules, "ja-JP")
+
+	wantProtoError := &proto.Error{
+		Msg:            "validation failed: min=20 of Age",
+		DefaultViewMsg: "小さすぎます（最小20）",
+		FieldViolations: []*proto.ValidationError_FieldViolation{
+			{
+				Field:          "Age",
+				Code:           "min",
+				Param:          "20",
+				DefaultViewMsg: "小さすぎます（最小20）",
+			},
+		},
+	}
+
+	fatalassert.Equal(t, wantProtoError, protoError)
+
+	protoError, err := validate.VErrorsToProtoError(validator.Errors{{Field: "Age", Tag: "min", Param: "20"}})
+	fatalassert.NoError(t, err)
+	fatalassert.Equal(t, "Age is less than 20", protoError.DefaultViewMsg)
+}